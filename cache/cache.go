@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores opaque values by key. Implementations must be safe for
+// concurrent use. A ttl of zero means the entry does not expire.
+type Cache interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// Stats counts cache lookups.
+type Stats struct {
+	hits   uint64
+	misses uint64
+}
+
+func (s *Stats) Hit()  { atomic.AddUint64(&s.hits, 1) }
+func (s *Stats) Miss() { atomic.AddUint64(&s.misses, 1) }
+
+func (s *Stats) Hits() uint64   { return atomic.LoadUint64(&s.hits) }
+func (s *Stats) Misses() uint64 { return atomic.LoadUint64(&s.misses) }
+
+// HitRate returns the fraction of lookups served from the cache.
+func (s *Stats) HitRate() float64 {
+	h, m := s.Hits(), s.Misses()
+	if h+m == 0 {
+		return 0
+	}
+	return float64(h) / float64(h+m)
+}
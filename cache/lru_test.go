@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lruKeys(l *LRU) []string {
+	var keys []string
+	for el := l.ll.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*lruEntry).key)
+	}
+	return keys
+}
+
+func TestLRUEviction(t *testing.T) {
+	l := NewLRU(3)
+	for _, k := range []string{"a", "b", "c"} {
+		assert.NoError(t, l.Set(k, []byte(k), 0))
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, lruKeys(l))
+
+	// Reads and overwrites make an entry the most recently used.
+	_, ok, _ := l.Get("a")
+	assert.True(t, ok)
+	assert.NoError(t, l.Set("b", []byte("b2"), 0))
+	assert.Equal(t, []string{"b", "a", "c"}, lruKeys(l))
+
+	assert.NoError(t, l.Set("d", []byte("d"), 0))
+	assert.Equal(t, []string{"d", "b", "a"}, lruKeys(l))
+	_, ok, _ = l.Get("c")
+	assert.False(t, ok)
+	v, ok, _ := l.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "b2", string(v))
+
+	assert.NoError(t, l.Delete("a"))
+	assert.NoError(t, l.Delete("missing"))
+	assert.Equal(t, []string{"b", "d"}, lruKeys(l))
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestLRUUnbounded(t *testing.T) {
+	l := NewLRU(0)
+	for i := 0; i < 100; i++ {
+		l.Set(string(rune('a'+i)), nil, 0)
+	}
+	assert.Equal(t, 100, l.Len())
+}
+
+func TestLRUExpiry(t *testing.T) {
+	l := NewLRU(10)
+	l.Set("short", []byte("v"), time.Millisecond)
+	l.Set("long", []byte("v"), time.Hour)
+	l.Set("forever", []byte("v"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	// Expired entries count until a read finds them.
+	assert.Equal(t, 3, l.Len())
+	_, ok, err := l.Get("short")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 2, l.Len())
+	for _, k := range []string{"long", "forever"} {
+		_, ok, _ = l.Get(k)
+		assert.True(t, ok, k)
+	}
+
+	// Setting an entry again replaces its expiry.
+	l.Set("long", []byte("v"), time.Millisecond)
+	l.Set("short", []byte("v"), 0)
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = l.Get("long")
+	assert.False(t, ok)
+	_, ok, _ = l.Get("short")
+	assert.True(t, ok)
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Memcached is a Cache backed by a memcached server, so several API
+// instances can share one cache.
+type Memcached struct {
+	client *memcache.Client
+}
+
+// NewMemcached returns a client for the server at addr. It keeps up to
+// maxIdle connections open between calls.
+func NewMemcached(addr string, maxIdle int) *Memcached {
+	c := memcache.New(addr)
+	c.Timeout = time.Second
+	c.MaxIdleConns = maxIdle
+	return &Memcached{client: c}
+}
+
+func (m *Memcached) Get(key string) ([]byte, bool, error) {
+	it, err := m.client.Get(mcKey(key))
+	if err == memcache.ErrCacheMiss {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return it.Value, true, nil
+}
+
+func (m *Memcached) Set(key string, value []byte, ttl time.Duration) error {
+	exp := int32(ttl / time.Second)
+	if ttl > 0 && exp == 0 {
+		exp = 1
+	}
+	return m.client.Set(&memcache.Item{Key: mcKey(key), Value: value, Expiration: exp})
+}
+
+func (m *Memcached) Delete(key string) error {
+	err := m.client.Delete(mcKey(key))
+	if err == memcache.ErrCacheMiss {
+		return nil
+	}
+	return err
+}
+
+// mcKey hashes keys memcached would reject: over 250 bytes or holding
+// whitespace or control characters.
+func mcKey(key string) string {
+	ok := len(key) <= 250
+	for i := 0; ok && i < len(key); i++ {
+		ok = key[i] > ' ' && key[i] != 0x7f
+	}
+	if ok {
+		return key
+	}
+	sum := sha1.Sum([]byte(key))
+	return "h:" + hex.EncodeToString(sum[:])
+}
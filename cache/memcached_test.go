@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMemcached serves gets, set and delete of the memcached text protocol
+// from a map, recording the commands it receives. Like memcached it refuses
+// values over maxSize bytes. Replies overrides the reply to commands
+// starting with a prefix; an override not ending in CRLF is cut short by
+// closing the connection after it.
+type fakeMemcached struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	items   map[string][]byte
+	cmds    []string
+	accepts int
+	replies map[string]string
+	maxSize int
+}
+
+func newFakeMemcached(t *testing.T) *fakeMemcached {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeMemcached{
+		ln:      ln,
+		items:   make(map[string][]byte),
+		replies: make(map[string]string),
+		maxSize: 1 << 20,
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.accepts++
+			s.mu.Unlock()
+			go s.serve(nc)
+		}
+	}()
+	return s
+}
+
+func (s *fakeMemcached) serve(nc net.Conn) {
+	defer nc.Close()
+	r := bufio.NewReader(nc)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSuffix(line, "\r\n")
+		f := strings.Fields(line)
+		var data []byte
+		if len(f) == 5 && f[0] == "set" {
+			n, _ := strconv.Atoi(f[4])
+			data = make([]byte, n+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			data = data[:n]
+		}
+		s.mu.Lock()
+		s.cmds = append(s.cmds, line)
+		reply, override := "", false
+		for prefix, r := range s.replies {
+			if strings.HasPrefix(line, prefix) {
+				reply, override = r, true
+			}
+		}
+		if !override {
+			reply = s.reply(f, data)
+		}
+		s.mu.Unlock()
+		if _, err := io.WriteString(nc, reply); err != nil {
+			return
+		}
+		if override && !strings.HasSuffix(reply, "\r\n") {
+			return
+		}
+	}
+}
+
+func (s *fakeMemcached) reply(f []string, data []byte) string {
+	switch {
+	case len(f) == 2 && f[0] == "gets":
+		v, ok := s.items[f[1]]
+		if !ok {
+			return "END\r\n"
+		}
+		return fmt.Sprintf("VALUE %s 0 %d 1\r\n%s\r\nEND\r\n", f[1], len(v), v)
+	case len(f) == 5 && f[0] == "set":
+		if len(data) > s.maxSize {
+			return "SERVER_ERROR object too large for cache\r\n"
+		}
+		s.items[f[1]] = data
+		return "STORED\r\n"
+	case len(f) == 2 && f[0] == "delete":
+		if _, ok := s.items[f[1]]; !ok {
+			return "NOT_FOUND\r\n"
+		}
+		delete(s.items, f[1])
+		return "DELETED\r\n"
+	}
+	return "ERROR\r\n"
+}
+
+func (s *fakeMemcached) commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cmds...)
+}
+
+func (s *fakeMemcached) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepts
+}
+
+func (s *fakeMemcached) setReply(prefix, reply string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies[prefix] = reply
+}
+
+func (s *fakeMemcached) clearReply(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.replies, prefix)
+}
+
+func TestMemcached(t *testing.T) {
+	s := newFakeMemcached(t)
+	m := NewMemcached(s.ln.Addr().String(), 1)
+
+	_, ok, err := m.Get("k")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	value := []byte("line\r\nEND\r\n\x00binary")
+	assert.NoError(t, m.Set("k", value, 0))
+	v, ok, err := m.Get("k")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, value, v)
+
+	assert.NoError(t, m.Delete("k"))
+	assert.NoError(t, m.Delete("k"))
+	_, ok, _ = m.Get("k")
+	assert.False(t, ok)
+
+	// TTLs are sent in whole seconds, rounding sub-second ones up.
+	m.Set("a", nil, 500*time.Millisecond)
+	m.Set("b", nil, 3*time.Second)
+	cmds := s.commands()
+	assert.Equal(t, []string{"set a 0 1 0", "set b 0 3 0"}, cmds[len(cmds)-2:])
+
+	// Sequential calls reuse the idle connection.
+	assert.Equal(t, 1, s.connections())
+}
+
+func TestMemcachedKeys(t *testing.T) {
+	s := newFakeMemcached(t)
+	m := NewMemcached(s.ln.Addr().String(), 1)
+	for _, key := range []string{"with space", "ctl\x01", strings.Repeat("k", 251)} {
+		assert.NoError(t, m.Set(key, []byte(key), 0))
+		v, ok, err := m.Get(key)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, key, string(v))
+	}
+	for _, cmd := range s.commands() {
+		f := strings.Fields(cmd)
+		if assert.True(t, len(f) >= 2, cmd) {
+			assert.True(t, strings.HasPrefix(f[1], "h:"), cmd)
+			assert.Len(t, f[1], 42)
+		}
+	}
+	assert.Equal(t, "plain:key-1", mcKey("plain:key-1"))
+	assert.Equal(t, strings.Repeat("k", 250), mcKey(strings.Repeat("k", 250)))
+}
+
+func TestMemcachedErrors(t *testing.T) {
+	s := newFakeMemcached(t)
+	m := NewMemcached(s.ln.Addr().String(), 1)
+	assert.NoError(t, m.Set("k", []byte("v"), 0))
+
+	for _, tc := range []struct {
+		name, prefix, reply string
+		call                func() error
+	}{
+		{"set server error", "set", "SERVER_ERROR out of memory\r\n", func() error {
+			return m.Set("k", []byte("v"), 0)
+		}},
+		{"get server error", "gets", "SERVER_ERROR out of memory\r\n", func() error {
+			_, _, err := m.Get("k")
+			return err
+		}},
+		{"delete server error", "delete", "SERVER_ERROR busy\r\n", func() error {
+			return m.Delete("k")
+		}},
+		{"short value", "gets", "VALUE k 0 10 1\r\nabc", func() error {
+			_, _, err := m.Get("k")
+			return err
+		}},
+		{"short reply line", "gets", "VALU", func() error {
+			_, _, err := m.Get("k")
+			return err
+		}},
+		{"corrupt value", "gets", "VALUE k 0 1 1\r\nabc\r\nEND\r\n", func() error {
+			_, _, err := m.Get("k")
+			return err
+		}},
+	} {
+		s.setReply(tc.prefix, tc.reply)
+		before := s.connections()
+		assert.Error(t, tc.call(), tc.name)
+		s.clearReply(tc.prefix)
+
+		// The failed connection is dropped, as its stream position is
+		// unknown, and the next call works on a fresh one.
+		v, ok, err := m.Get("k")
+		assert.NoError(t, err, tc.name)
+		assert.True(t, ok, tc.name)
+		assert.Equal(t, "v", string(v), tc.name)
+		assert.Equal(t, before+1, s.connections(), tc.name)
+	}
+
+	m = NewMemcached("127.0.0.1:1", 1)
+	_, _, err := m.Get("k")
+	assert.Error(t, err)
+}
+
+func TestMemcachedConnectionReuse(t *testing.T) {
+	s := newFakeMemcached(t)
+	m := NewMemcached(s.ln.Addr().String(), 1)
+
+	// Misses and deletes of absent keys are answered in protocol and leave
+	// the connection usable.
+	for i := 0; i < 3; i++ {
+		_, ok, err := m.Get("absent")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.NoError(t, m.Delete("absent"))
+	}
+	assert.Equal(t, 1, s.connections())
+}
+
+func TestMemcachedOversized(t *testing.T) {
+	s := newFakeMemcached(t)
+	s.maxSize = 16
+	m := NewMemcached(s.ln.Addr().String(), 1)
+
+	err := m.Set("big", make([]byte, 17), 0)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "object too large")
+	}
+	_, ok, err := m.Get("big")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, m.Set("fits", make([]byte, 16), 0))
+	v, ok, err := m.Get("fits")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, v, 16)
+}
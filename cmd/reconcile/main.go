@@ -0,0 +1,27 @@
+// Command reconcile recomputes the denormalized favorites, comments and
+// followers counters from the rows they summarize, repairing any drift.
+package main
+
+import (
+	"log"
+
+	"golang-starter-pack/db"
+	"golang-starter-pack/store"
+)
+
+func main() {
+	d := db.New()
+	defer d.Close()
+	d.LogMode(false)
+	db.AutoMigrate(d)
+
+	items, err := store.NewItemStore(d).ReconcileCounters()
+	if err != nil {
+		log.Fatal(err)
+	}
+	players, err := store.NewPlayerStore(d).ReconcileCounters()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("reconciled counters of %d items and %d players", items, players)
+}
@@ -1,10 +1,9 @@
 package db
 
 import (
-	"fmt"
-
 	"os"
 
+	"golang-starter-pack/logging"
 	"golang-starter-pack/model"
 
 	"github.com/jinzhu/gorm"
@@ -14,17 +13,18 @@ import (
 func New() *gorm.DB {
 	db, err := gorm.Open("sqlite3", "./example.db")
 	if err != nil {
-		fmt.Println("storage err: ", err)
+		logging.Default.Error("opening database", "err", err)
 	}
 	db.DB().SetMaxIdleConns(3)
-	db.LogMode(true)
+	// Statements are logged through Log instead of gorm's own logger.
+	db.LogMode(false)
 	return db
 }
 
 func TestDB() *gorm.DB {
 	db, err := gorm.Open("sqlite3", "./../example_test.db")
 	if err != nil {
-		fmt.Println("storage err: ", err)
+		logging.Default.Error("opening database", "err", err)
 	}
 	db.DB().SetMaxIdleConns(3)
 	db.LogMode(false)
@@ -38,13 +38,47 @@ func DropTestDB() error {
 	return nil
 }
 
+// models are the tables AutoMigrate creates and PendingMigrations checks.
+var models = []interface{}{
+	&model.Player{},
+	&model.Follow{},
+	&model.Item{},
+	&model.Comment{},
+	&model.Tag{},
+	&model.LoginAttempt{},
+	&model.RecoveryCode{},
+	&model.APIKey{},
+}
+
 //TODO: err check
 func AutoMigrate(db *gorm.DB) {
-	db.AutoMigrate(
-		&model.Player{},
-		&model.Follow{},
-		&model.Item{},
-		&model.Comment{},
-		&model.Tag{},
-	)
+	db.AutoMigrate(models...)
+}
+
+// PendingMigrations lists the tables and columns AutoMigrate would still
+// create, so an instance started against an older schema is not ready.
+func PendingMigrations(db *gorm.DB) []string {
+	var pending []string
+	seen := make(map[string]bool)
+	dialect := db.Dialect()
+	for _, m := range models {
+		scope := db.NewScope(m)
+		table := scope.TableName()
+		if !dialect.HasTable(table) {
+			pending = append(pending, "table "+table)
+			continue
+		}
+		for _, f := range scope.GetModelStruct().StructFields {
+			if f.IsNormal && !f.IsIgnored && !dialect.HasColumn(table, f.DBName) {
+				pending = append(pending, "column "+table+"."+f.DBName)
+			}
+			if r := f.Relationship; r != nil && r.Kind == "many_to_many" && r.JoinTableHandler != nil {
+				if join := r.JoinTableHandler.Table(db); !seen[join] && !dialect.HasTable(join) {
+					seen[join] = true
+					pending = append(pending, "table "+join)
+				}
+			}
+		}
+	}
+	return pending
 }
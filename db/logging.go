@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"golang-starter-pack/logging"
+)
+
+const (
+	loggerKey   = "logging:logger"
+	logStartKey = "logging:start"
+)
+
+// Log writes every statement run through db to l at debug level, and
+// failed statements at error level, with the request fields of the
+// statement's context. Bound values are never logged, only placeholders.
+func Log(db *gorm.DB, l *logging.Logger) {
+	db.InstantSet(loggerKey, l)
+	cb := db.Callback()
+	register := func(operation string, p func() *gorm.CallbackProcessor, name string) {
+		p().Before(name).Register("logging:before_"+operation, func(scope *gorm.Scope) {
+			scope.InstanceSet(logStartKey, time.Now())
+		})
+		p().After(name).Register("logging:after_"+operation, func(scope *gorm.Scope) {
+			start, ok := scope.InstanceGet(logStartKey)
+			if !ok {
+				return
+			}
+			ctx := context.Background()
+			if v, ok := scope.Get(requestContextKey); ok {
+				ctx = v.(context.Context)
+			}
+			var err error
+			if scope.HasError() && !gorm.IsRecordNotFoundError(scope.DB().Error) {
+				err = scope.DB().Error
+			}
+			logStatement(l.Ctx(ctx), operation, scope.SQL, start.(time.Time), scope.DB().RowsAffected, err)
+		})
+	}
+	register("create", cb.Create, "gorm:create")
+	register("query", cb.Query, "gorm:query")
+	register("update", cb.Update, "gorm:update")
+	register("delete", cb.Delete, "gorm:delete")
+	register("row_query", cb.RowQuery, "gorm:row_query")
+}
+
+func logExec(d *gorm.DB, ctx context.Context, sql string, start time.Time, res *gorm.DB) {
+	if v, ok := d.Get(loggerKey); ok {
+		logStatement(v.(*logging.Logger).Ctx(ctx), "exec", sql, start, res.RowsAffected, res.Error)
+	}
+}
+
+func logStatement(l *logging.Logger, operation, sql string, start time.Time, rows int64, err error) {
+	kv := []interface{}{"operation", operation, "sql", sql, "duration_ms", logging.Millis(time.Since(start)), "rows", rows}
+	if err != nil {
+		l.Error("sql failed", append(kv, "err", err)...)
+		return
+	}
+	if l.Enabled(logging.LevelDebug) {
+		l.Debug("sql", kv...)
+	}
+}
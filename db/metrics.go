@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang-starter-pack/metrics"
+)
+
+var (
+	queryDuration = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "db_query_duration_seconds", Help: "SQL statement latency by operation."},
+		[]string{"operation"})
+	queryErrors = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total", Help: "Failed SQL statements by operation."},
+		[]string{"operation"})
+	instrumentOnce sync.Once
+)
+
+const queryStartKey = "metrics:query_start"
+
+// Instrument records the duration and errors of every statement run
+// through db, and exposes its connection pool statistics. Metric names are
+// global, so only the first database passed in is instrumented.
+func Instrument(db *gorm.DB) {
+	instrumentOnce.Do(func() {
+		cb := db.Callback()
+		// Processors are mutated by Register, so each needs a fresh one.
+		register := func(operation string, p func() *gorm.CallbackProcessor, name string) {
+			p().Before(name).Register("metrics:before_"+operation, func(scope *gorm.Scope) {
+				scope.InstanceSet(queryStartKey, time.Now())
+			})
+			p().After(name).Register("metrics:after_"+operation, func(scope *gorm.Scope) {
+				observeQuery(scope, operation)
+			})
+		}
+		register("create", cb.Create, "gorm:create")
+		register("query", cb.Query, "gorm:query")
+		register("update", cb.Update, "gorm:update")
+		register("delete", cb.Delete, "gorm:delete")
+		register("row_query", cb.RowQuery, "gorm:row_query")
+
+		stats := func(fn func(s sql.DBStats) float64) func() float64 {
+			return func() float64 { return fn(db.DB().Stats()) }
+		}
+		f := metrics.Factory
+		f.NewGaugeFunc(prometheus.GaugeOpts{Name: "db_open_connections", Help: "Open database connections."},
+			stats(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+		f.NewGaugeFunc(prometheus.GaugeOpts{Name: "db_in_use_connections", Help: "Database connections in use."},
+			stats(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+		f.NewGaugeFunc(prometheus.GaugeOpts{Name: "db_idle_connections", Help: "Idle database connections."},
+			stats(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+		f.NewCounterFunc(prometheus.CounterOpts{Name: "db_wait_count_total", Help: "Waits for a database connection."},
+			stats(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+		f.NewCounterFunc(prometheus.CounterOpts{Name: "db_wait_duration_seconds_total", Help: "Time spent waiting for a database connection."},
+			stats(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	})
+}
+
+func observeQuery(scope *gorm.Scope, operation string) {
+	if v, ok := scope.InstanceGet(queryStartKey); ok {
+		queryDuration.WithLabelValues(operation).Observe(time.Since(v.(time.Time)).Seconds())
+	}
+	if scope.HasError() && !gorm.IsRecordNotFoundError(scope.DB().Error) {
+		queryErrors.WithLabelValues(operation).Inc()
+	}
+}
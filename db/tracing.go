@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestContextKey = "request:context"
+	traceSpanKey      = "tracing:span"
+	tracerName        = "golang-starter-pack/db"
+)
+
+// WithContext returns a handle on db whose statements are traced as
+// children of the span in ctx and logged with its request fields.
+func WithContext(db *gorm.DB, ctx context.Context) *gorm.DB {
+	return db.Set(requestContextKey, ctx)
+}
+
+// Trace records an OpenTelemetry client span for every statement run
+// through a handle obtained from WithContext, with the database semantic
+// conventions' attributes. Statements without a context are not traced.
+//
+// The contrib otelgorm instrumentation only supports gorm v2, so these are
+// plain gorm v1 callbacks.
+func Trace(db *gorm.DB) {
+	cb := db.Callback()
+	register := func(operation, verb string, p func() *gorm.CallbackProcessor, name string) {
+		p().Before(name).Register("tracing:before_"+operation, func(scope *gorm.Scope) {
+			v, ok := scope.Get(requestContextKey)
+			if !ok {
+				return
+			}
+			table := scope.TableName()
+			_, span := startSpan(v.(context.Context), scope.Dialect().GetName(), verb, table)
+			scope.InstanceSet(traceSpanKey, span)
+		})
+		p().After(name).Register("tracing:after_"+operation, func(scope *gorm.Scope) {
+			v, ok := scope.InstanceGet(traceSpanKey)
+			if !ok {
+				return
+			}
+			span := v.(trace.Span)
+			span.SetAttributes(semconv.DBQueryText(scope.SQL))
+			if scope.HasError() && !gorm.IsRecordNotFoundError(scope.DB().Error) {
+				recordError(span, scope.DB().Error)
+			}
+			span.End()
+		})
+	}
+	register("create", "INSERT", cb.Create, "gorm:create")
+	register("query", "SELECT", cb.Query, "gorm:query")
+	register("update", "UPDATE", cb.Update, "gorm:update")
+	register("delete", "DELETE", cb.Delete, "gorm:delete")
+	register("row_query", "SELECT", cb.RowQuery, "gorm:row_query")
+}
+
+// Exec runs raw SQL, which bypasses gorm's callbacks, tracing it like
+// other statements when d carries a context and logging it when d was
+// passed to Log.
+func Exec(d *gorm.DB, sql string, values ...interface{}) *gorm.DB {
+	start := time.Now()
+	v, ok := d.Get(requestContextKey)
+	if !ok {
+		res := d.Exec(sql, values...)
+		logExec(d, context.Background(), sql, start, res)
+		return res
+	}
+	verb := strings.ToUpper(strings.SplitN(strings.TrimSpace(sql), " ", 2)[0])
+	_, span := startSpan(v.(context.Context), d.Dialect().GetName(), verb, "")
+	defer span.End()
+	span.SetAttributes(semconv.DBQueryText(sql))
+	res := d.Exec(sql, values...)
+	recordError(span, res.Error)
+	logExec(d, v.(context.Context), sql, start, res)
+	return res
+}
+
+// startSpan starts a statement's span, named "<operation> <table>" as the
+// semantic conventions ask.
+func startSpan(ctx context.Context, dialect, operation, table string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{dbSystem(dialect), semconv.DBOperationName(operation)}
+	name := operation
+	if table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+		name += " " + table
+	}
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// dbSystem maps a gorm dialect name onto db.system.name.
+func dbSystem(dialect string) attribute.KeyValue {
+	switch dialect {
+	case "sqlite3":
+		return semconv.DBSystemNameSQLite
+	case "postgres":
+		return semconv.DBSystemNamePostgreSQL
+	case "mysql":
+		return semconv.DBSystemNameMySQL
+	case "mssql":
+		return semconv.DBSystemNameMicrosoftSQLServer
+	}
+	return semconv.DBSystemNameKey.String(dialect)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/cache"
+	"golang-starter-pack/utils"
+)
+
+// adminOnly rejects requests whose token does not belong to an admin player.
+// It must run after the JWT middleware.
+func (h *Handler) adminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		u, err := h.players(c).GetByID(playerIDFromToken(c))
+		if err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		if u == nil || !u.Admin {
+			return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+		}
+		return next(c)
+	}
+}
+
+func (h *Handler) UnlockPlayer(c echo.Context) error {
+	u, err := h.players(c).GetByUsername(c.Param("username"))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	u.FailedLogins = 0
+	u.LastFailedLoginAt = nil
+	u.LockedUntil = nil
+	if err := h.players(c).UpdateLoginState(u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newResultResponse())
+}
+
+// CacheStats reports hit rates of the item store cache, if one is in use.
+func (h *Handler) CacheStats(c echo.Context) error {
+	cs, ok := h.items(c).(interface{ Stats() *cache.Stats })
+	if !ok {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	return c.JSON(http.StatusOK, newCacheStatsResponse(cs.Stats()))
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+func (h *Handler) CreateAPIKey(c echo.Context) error {
+	var k model.APIKey
+	req := &apiKeyCreateRequest{}
+	if err := req.bind(c, &k); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	k.Prefix = prefix
+	k.KeyHash = utils.HashAPIKey(key)
+	if err := h.players(c).CreateAPIKey(&k); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusCreated, newAPIKeyResponse(&k, key))
+}
+
+func (h *Handler) APIKeys(c echo.Context) error {
+	keys, err := h.players(c).ListAPIKeys(playerIDFromToken(c))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newAPIKeyListResponse(keys))
+}
+
+func (h *Handler) RevokeAPIKey(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.RenderError(c, http.StatusBadRequest, err)
+	}
+	k, err := h.players(c).GetAPIKey(playerIDFromToken(c), uint(id64))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if k == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	if err := h.players(c).RevokeAPIKey(k); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newResultResponse())
+}
+
+// validateAPIKey resolves a presented key for the auth middleware and
+// records when it was last used. Revoked keys are soft deleted, so they
+// are not found like unknown ones.
+func (h *Handler) validateAPIKey(key string) (uint, []string, error) {
+	k, err := h.playerStore.GetAPIKeyByHash(utils.HashAPIKey(key))
+	if err != nil {
+		return 0, nil, err
+	}
+	if k == nil {
+		return 0, nil, middleware.ErrAPIKeyInvalid
+	}
+	if err := h.playerStore.TouchAPIKey(k); err != nil {
+		return 0, nil, err
+	}
+	return k.PlayerID, k.ScopeList(), nil
+}
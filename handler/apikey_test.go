@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/model"
+	"golang-starter-pack/player"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+)
+
+func TestAPIKeyScopes(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	reqJSON := `{"apiKey":{"name":"ci","scopes":["items:read","comments:write"]}}`
+	req := httptest.NewRequest(echo.POST, "/api/player/keys", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if !assert.Equal(t, http.StatusCreated, rec.Code) {
+		return
+	}
+	var k singleAPIKeyResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &k))
+	assert.NotEmpty(t, k.APIKey.Key)
+	assert.Equal(t, []string{"items:read", "comments:write"}, k.APIKey.Scopes)
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, "ApiKey "+k.APIKey.Key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, do(echo.GET, "/api/items/feed", ""))
+	assert.Equal(t, http.StatusCreated, do(echo.POST, "/api/items/item2-slug/comments", `{"comment":{"body":"via key"}}`))
+	assert.Equal(t, http.StatusForbidden, do(echo.POST, "/api/items", `{"item":{"title":"t","description":"d","body":"b"}}`))
+	assert.Equal(t, http.StatusForbidden, do(echo.GET, "/api/player/keys", ""))
+
+	req = httptest.NewRequest(echo.GET, "/api/player/keys", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var l apiKeyListResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &l))
+		if assert.Len(t, l.APIKeys, 1) {
+			assert.Empty(t, l.APIKeys[0].Key)
+			assert.NotNil(t, l.APIKeys[0].LastUsedAt)
+		}
+	}
+
+	req = httptest.NewRequest(echo.DELETE, "/api/player/keys/1", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusForbidden, do(echo.GET, "/api/items/feed", ""))
+}
+
+func TestAPIKeyProfileWrite(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	req := httptest.NewRequest(echo.POST, "/api/player/keys", strings.NewReader(`{"apiKey":{"name":"profile","scopes":["profile:write"]}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if !assert.Equal(t, http.StatusCreated, rec.Code) {
+		return
+	}
+	var k singleAPIKeyResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &k))
+	do := func(method, path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderAuthorization, "ApiKey "+k.APIKey.Key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// profile:write covers the profile, but taking over the account by
+	// changing its email or password needs a session.
+	assert.Equal(t, http.StatusOK, do(echo.PUT, "/api/player", echo.MIMEApplicationJSON, `{"player":{"email":"player1@realworld.io","bio":"via key"}}`).Code)
+	assert.Equal(t, http.StatusOK, do(echo.PATCH, "/api/player", utils.MIMEMergePatch, `{"player":{"image":"http://realworld.io/key.jpg"}}`).Code)
+	rec = do(echo.PUT, "/api/player", echo.MIMEApplicationJSON, `{"player":{"email":"attacker@realworld.io"}}`)
+	if assert.Equal(t, http.StatusForbidden, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeForbidden, res.Errors.Code)
+	}
+	assert.Equal(t, http.StatusForbidden, do(echo.PATCH, "/api/player", utils.MIMEMergePatch, `{"player":{"password":"takeover1"}}`).Code)
+	assert.Equal(t, http.StatusForbidden, do(echo.PATCH, "/api/player", utils.MIMEJSONPatch, `[{"op":"replace","path":"/player/email","value":"attacker@realworld.io"}]`).Code)
+
+	graphQL := func(input string) graphQLResponse {
+		rec := do(echo.POST, "/api/graphql", echo.MIMEApplicationJSON, `{"query":"mutation { updatePlayer(input: `+input+`) { email bio } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var res graphQLResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+	if res := graphQL(`{bio: \"graph bio\"}`); assert.Empty(t, res.Errors) {
+		assert.Equal(t, "graph bio", res.Data["updatePlayer"].(map[string]interface{})["bio"])
+	}
+	for _, input := range []string{`{email: \"attacker@realworld.io\"}`, `{password: \"takeover1\"}`} {
+		if res := graphQL(input); assert.Len(t, res.Errors, 1, input) {
+			assert.Equal(t, utils.CodeForbidden, res.Errors[0].Extensions["code"])
+		}
+	}
+
+	u, _ := us.GetByID(1)
+	assert.Equal(t, "player1@realworld.io", u.Email)
+	assert.True(t, u.CheckPassword("secret"))
+	assert.Equal(t, "graph bio", *u.Bio)
+
+	// Sessions may still change both.
+	req = httptest.NewRequest(echo.PUT, "/api/player", strings.NewReader(`{"player":{"email":"new@realworld.io","password":"newsecret1"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// failingKeyStore fails API key lookups the way a broken database does.
+type failingKeyStore struct {
+	player.Store
+}
+
+func (failingKeyStore) GetAPIKeyByHash(string) (*model.APIKey, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestAPIKeyCaseStoreError(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	NewHandler(failingKeyStore{us}, as).Register(e.Group("/api"))
+
+	req := httptest.NewRequest(echo.GET, "/api/items/feed", nil)
+	req.Header.Set(echo.HeaderAuthorization, "ApiKey rwk_unknown")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if assert.Equal(t, http.StatusInternalServerError, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeInternal, res.Errors.Code)
+		assert.NotContains(t, rec.Body.String(), "locked")
+	}
+
+	e = router.New()
+	h.Register(e.Group("/api"))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
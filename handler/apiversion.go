@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/router/middleware"
+)
+
+// apiMediaType names a version in Accept headers.
+const apiMediaType = "application/vnd.starter.v%d+json"
+
+// Version 1 is the original API. Later versions share its route table,
+// so its handlers and requests, and can only reshape what handlers render
+// with c.JSON: each has a mapper from the shapes of the version before it.
+// Blobs and empty responses are the same in every version.
+var apiMappers = map[int]func(interface{}) interface{}{
+	2: mapV2,
+}
+
+func newAPIVersions() []middleware.APIVersion {
+	return []middleware.APIVersion{{Number: 1}, {Number: 2}}
+}
+
+// DeprecateAPIVersion announces that a version is deprecated since at and
+// stops working at sunset, which may be zero when not yet decided.
+func (h *Handler) DeprecateAPIVersion(version int, at, sunset time.Time) {
+	for i := range h.apiVersions {
+		if h.apiVersions[i].Number == version {
+			h.apiVersions[i].Deprecated = at
+			h.apiVersions[i].Sunset = sunset
+		}
+	}
+}
+
+func (h *Handler) apiVersioning() echo.MiddlewareFunc {
+	return middleware.APIVersionWithConfig(middleware.APIVersionConfig{
+		Prefix:    "/api",
+		Versions:  h.apiVersions,
+		Default:   1,
+		MediaType: apiMediaType,
+		Map:       mapResponse,
+	})
+}
+
+// mapResponse brings a version 1 response up to version.
+func mapResponse(version int, v interface{}) interface{} {
+	for n := 2; n <= version; n++ {
+		if m := apiMappers[n]; m != nil {
+			v = m(v)
+		}
+	}
+	return v
+}
+
+// Version 2 moves the token out of the player, groups an item's counters
+// and names the size of a list total.
+
+type playerResponseV2 struct {
+	Player struct {
+		Username string  `json:"username"`
+		Email    string  `json:"email"`
+		Bio      *string `json:"bio"`
+		Image    *string `json:"image"`
+	} `json:"player"`
+	Token string `json:"token"`
+}
+
+type itemStatsV2 struct {
+	Favorites int `json:"favorites"`
+	Comments  int `json:"comments"`
+}
+
+type itemResponseV2 struct {
+	Slug        string      `json:"slug"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Body        string      `json:"body"`
+	TagList     []string    `json:"tagList"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+	Favorited   bool        `json:"favorited"`
+	Stats       itemStatsV2 `json:"stats"`
+	Author      interface{} `json:"author"`
+}
+
+type singleItemResponseV2 struct {
+	Item *itemResponseV2 `json:"item"`
+}
+
+type itemListResponseV2 struct {
+	Items []*itemResponseV2 `json:"items"`
+	Total int               `json:"total"`
+
+	v1 *itemListResponse
+}
+
+// Table renders the rows of version 1 under the names of version 2.
+func (r *itemListResponseV2) Table() ([]string, [][]string) {
+	_, rows := r.v1.Table()
+	header := append([]string(nil), itemColumns...)
+	for i, c := range header {
+		switch c {
+		case "favoritesCount":
+			header[i] = "stats.favorites"
+		case "commentsCount":
+			header[i] = "stats.comments"
+		}
+	}
+	return header, rows
+}
+
+func mapV2(v interface{}) interface{} {
+	switch r := v.(type) {
+	case *playerResponse:
+		m := new(playerResponseV2)
+		m.Player.Username = r.Player.Username
+		m.Player.Email = r.Player.Email
+		m.Player.Bio = r.Player.Bio
+		m.Player.Image = r.Player.Image
+		m.Token = r.Player.Token
+		return m
+	case *singleItemResponse:
+		return &singleItemResponseV2{newItemV2(r.Item)}
+	case *itemListResponse:
+		m := &itemListResponseV2{Items: make([]*itemResponseV2, 0, len(r.Items)), Total: r.ItemsCount, v1: r}
+		for _, a := range r.Items {
+			m.Items = append(m.Items, newItemV2(a))
+		}
+		return m
+	}
+	return v
+}
+
+func newItemV2(a *itemResponse) *itemResponseV2 {
+	return &itemResponseV2{
+		Slug:        a.Slug,
+		Title:       a.Title,
+		Description: a.Description,
+		Body:        a.Body,
+		TagList:     a.TagList,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		Favorited:   a.Favorited,
+		Stats:       itemStatsV2{Favorites: a.FavoritesCount, Comments: a.CommentsCount},
+		Author:      a.Author,
+	}
+}
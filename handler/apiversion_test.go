@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/router"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+func TestAPIVersions(t *testing.T) {
+	h := NewHandler(us, as)
+	h.DeprecateAPIVersion(1, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	e := router.New()
+	e.Pre(middleware.StripAPIVersion("/api"))
+	h.Register(e.Group("/api"))
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.GET, path, nil)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, rec := range []*httptest.ResponseRecorder{get("/api/items/item1-slug", ""), get("/api/v1/items/item1-slug", "")} {
+		if assert.Equal(t, http.StatusOK, rec.Code) {
+			m := responseMap(rec.Body.Bytes(), "item")
+			assert.Equal(t, float64(1), m["commentsCount"])
+			assert.Equal(t, "1", rec.Header().Get(middleware.APIVersionHeader))
+			assert.Equal(t, "@1767225600", rec.Header().Get("Deprecation"))
+			assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+			assert.Equal(t, `</api/v2>; rel="successor-version"`, rec.Header().Get("Link"))
+		}
+	}
+
+	for _, rec := range []*httptest.ResponseRecorder{get("/api/v2/items/item1-slug", ""), get("/api/items/item1-slug", "application/vnd.starter.v2+json")} {
+		if assert.Equal(t, http.StatusOK, rec.Code) {
+			m := responseMap(rec.Body.Bytes(), "item")
+			assert.NotContains(t, m, "commentsCount")
+			assert.Equal(t, map[string]interface{}{"favorites": float64(0), "comments": float64(1)}, m["stats"])
+			assert.Equal(t, "player1", m["author"].(map[string]interface{})["username"])
+			assert.Equal(t, "2", rec.Header().Get(middleware.APIVersionHeader))
+			assert.Empty(t, rec.Header().Get("Deprecation"))
+		}
+	}
+
+	rec := get("/api/v2/player", "")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var r map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+		assert.NotEmpty(t, r["token"])
+		assert.NotContains(t, r["player"], "token")
+	}
+
+	rec = get("/api/v2/items?limit=1", "")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var r map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+		assert.Equal(t, float64(2), r["total"])
+		assert.Len(t, r["items"], 1)
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v9/items", "").Code)
+	assert.Equal(t, http.StatusNotAcceptable, get("/api/items", "application/vnd.starter.v9+json").Code)
+	// Errors look the same in every version.
+	rec = get("/api/v2/items/nope", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"errors"`)
+}
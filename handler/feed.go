@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/item"
+	"golang-starter-pack/markdown"
+	"golang-starter-pack/model"
+	"golang-starter-pack/utils"
+)
+
+// feedSize is the number of most recent items a feed carries.
+const feedSize = 20
+
+const (
+	mimeAtom = "application/atom+xml; charset=UTF-8"
+	mimeRSS  = "application/rss+xml; charset=UTF-8"
+)
+
+// feed is the format-independent content of a feed.
+type feed struct {
+	title     string
+	self      string
+	alternate string
+	items     []model.Item
+}
+
+// ItemsFeed serves the latest items as /items.rss or /items.atom.
+func (h *Handler) ItemsFeed(c echo.Context) error {
+	items, _, err := h.items(c).List(item.OrderRecent, 0, feedSize)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return h.renderFeed(c, &feed{title: "Items", alternate: "/api/items", items: items})
+}
+
+// AuthorFeed serves the latest items of a player.
+func (h *Handler) AuthorFeed(c echo.Context) error {
+	username := c.Param("username")
+	u, err := h.players(c).GetByUsername(username)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	items, _, err := h.items(c).ListByAuthor(username, item.OrderRecent, 0, feedSize)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return h.renderFeed(c, &feed{title: "Items by " + username, alternate: "/api/items?author=" + url.QueryEscape(username), items: items})
+}
+
+// TagFeed serves the latest items with a tag.
+func (h *Handler) TagFeed(c echo.Context) error {
+	tag := c.Param("tag")
+	items, _, err := h.items(c).ListByTag(tag, item.OrderRecent, 0, feedSize)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return h.renderFeed(c, &feed{title: "Items tagged " + tag, alternate: "/api/items?tag=" + url.QueryEscape(tag), items: items})
+}
+
+// renderFeed writes f as Atom or RSS, by the extension of the route, with
+// links under the public URL. The feed is updated when its most recently
+// updated item was, or now when it has none. Conditional requests are
+// answered on an ETag of the document only: the update time does not move
+// back when an item leaves the feed, so it cannot serve as Last-Modified.
+func (h *Handler) renderFeed(c echo.Context, f *feed) error {
+	atom := strings.HasSuffix(c.Path(), ".atom")
+	base := h.publicURL
+	f.self = base + c.Request().URL.Path
+	f.alternate = base + f.alternate
+
+	var updated time.Time
+	for _, a := range f.items {
+		if a.UpdatedAt.After(updated) {
+			updated = a.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	var (
+		doc         interface{}
+		contentType string
+	)
+	if atom {
+		doc, contentType = newAtomFeed(f, base, updated), mimeAtom
+	} else {
+		doc, contentType = newRSSFeed(f, base, updated), mimeRSS
+	}
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	body := append([]byte(xml.Header), b...)
+	if utils.NotModified(c, utils.ContentETag(contentType, body)) {
+		return nil
+	}
+	return c.Blob(http.StatusOK, contentType, body)
+}
+
+func itemURL(base string, a *model.Item) string {
+	return base + "/api/items/" + url.PathEscape(a.Slug)
+}
+
+func profileURL(base, username string) string {
+	return base + "/api/profiles/" + url.PathEscape(username)
+}
+
+// Atom, RFC 4287.
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     atomPerson     `xml:"author"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func newAtomFeed(f *feed, base string, updated time.Time) *atomFeed {
+	r := &atomFeed{
+		ID:      f.self,
+		Title:   f.title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.self},
+			{Rel: "alternate", Type: "application/json", Href: f.alternate},
+		},
+	}
+	for i := range f.items {
+		a := &f.items[i]
+		e := atomEntry{
+			ID:        itemURL(base, a),
+			Title:     a.Title,
+			Updated:   a.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: a.CreatedAt.UTC().Format(time.RFC3339),
+			Author:    atomPerson{Name: a.Author.Username, URI: profileURL(base, a.Author.Username)},
+			Link:      atomLink{Rel: "alternate", Type: "application/json", Href: itemURL(base, a)},
+			Summary:   a.Description,
+			Content:   atomText{Type: "html", Body: markdown.HTML(a.Body)},
+		}
+		for _, t := range a.Tags {
+			e.Categories = append(e.Categories, atomCategory{Term: t.Tag})
+		}
+		r.Entries = append(r.Entries, e)
+	}
+	return r
+}
+
+// RSS 2.0, with an atom:link to itself as feed validators recommend.
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func newRSSFeed(f *feed, base string, updated time.Time) *rssFeed {
+	r := &rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.title,
+			Link:        f.alternate,
+			Description: f.title,
+			Self:        atomLink{Rel: "self", Type: "application/rss+xml", Href: f.self},
+		},
+	}
+	if !updated.IsZero() {
+		r.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+	for i := range f.items {
+		a := &f.items[i]
+		it := rssItem{
+			Title:       a.Title,
+			Link:        itemURL(base, a),
+			GUID:        rssGUID{IsPermaLink: true, Value: itemURL(base, a)},
+			PubDate:     a.CreatedAt.UTC().Format(time.RFC1123Z),
+			Creator:     a.Author.Username,
+			Description: markdown.HTML(a.Body),
+		}
+		for _, t := range a.Tags {
+			it.Categories = append(it.Categories, t.Tag)
+		}
+		r.Channel.Items = append(r.Channel.Items, it)
+	}
+	return r
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router"
+)
+
+func TestFeeds(t *testing.T) {
+	tearDown()
+	setup()
+	h.SetPublicURL("https://starter.example/")
+	e := router.New()
+	h.Register(e.Group("/api"))
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.GET, path, nil)
+		req.Host = "attacker.example"
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	a := model.Item{
+		Slug:        "markdown-slug",
+		Title:       "Markdown & more",
+		Description: "markdown description",
+		Body:        "# Hello\n\nSome *emphasis*, [a link](https://example.com) and [a trap](javascript:alert(1)).\n\n<script>alert(1)</script>",
+		AuthorID:    1,
+		Tags:        []model.Tag{{Tag: "md"}},
+	}
+	if !assert.NoError(t, as.CreateItem(&a)) {
+		return
+	}
+
+	rec := get("/api/items.atom", nil)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, mimeAtom, rec.Header().Get(echo.HeaderContentType))
+		var f atomFeed
+		if assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &f)) && assert.Len(t, f.Entries, 3) {
+			assert.Equal(t, "https://starter.example/api/items.atom", f.ID)
+			newest := f.Entries[0]
+			assert.Equal(t, "https://starter.example/api/items/markdown-slug", newest.ID)
+			assert.Equal(t, "Markdown & more", newest.Title)
+			assert.Equal(t, "player1", newest.Author.Name)
+			assert.Equal(t, []atomCategory{{Term: "md"}}, newest.Categories)
+			assert.Equal(t, "html", newest.Content.Type)
+			assert.Equal(t, "<h1>Hello</h1>\n"+
+				`<p>Some <em>emphasis</em>, <a href="https://example.com" rel="nofollow">a link</a> and a trap.</p>`,
+				newest.Content.Body)
+			assert.Equal(t, newest.Updated, f.Updated)
+		}
+	}
+
+	rec = get("/api/items.rss", nil)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, mimeRSS, rec.Header().Get(echo.HeaderContentType))
+		var f struct {
+			Version string `xml:"version,attr"`
+			Items   []struct {
+				Title       string `xml:"title"`
+				GUID        string `xml:"guid"`
+				Description string `xml:"description"`
+			} `xml:"channel>item"`
+		}
+		if assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &f)) && assert.Len(t, f.Items, 3) {
+			assert.Equal(t, "2.0", f.Version)
+			assert.Equal(t, "https://starter.example/api/items/markdown-slug", f.Items[0].GUID)
+			assert.Contains(t, f.Items[0].Description, "<em>emphasis</em>")
+			assert.NotContains(t, rec.Body.String(), "<script>")
+			assert.NotContains(t, rec.Body.String(), "attacker.example")
+		}
+	}
+
+	rec = get("/api/profiles/player2/items.atom", nil)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var f atomFeed
+		if assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &f)) && assert.Len(t, f.Entries, 1) {
+			assert.Equal(t, "Items by player2", f.Title)
+			assert.Equal(t, "https://starter.example/api/items/item2-slug", f.Entries[0].ID)
+		}
+	}
+	assert.Equal(t, http.StatusNotFound, get("/api/profiles/nobody/items.atom", nil).Code)
+
+	// Unknown tags have an empty feed, as they list no items.
+	rec = get("/api/tags/nosuchtag/items.atom", nil)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var f atomFeed
+		assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &f))
+		assert.Empty(t, f.Entries)
+		updated, err := time.Parse(time.RFC3339, f.Updated)
+		if assert.NoError(t, err) {
+			assert.WithinDuration(t, time.Now(), updated, time.Minute)
+		}
+	}
+
+	rec = get("/api/tags/tag2/items.rss", nil)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Contains(t, rec.Body.String(), "item1-slug")
+		assert.NotContains(t, rec.Body.String(), "item2-slug")
+	}
+
+	// Conditional requests.
+	rec = get("/api/tags/md/items.atom", nil)
+	etag := rec.Header().Get("ETag")
+	if !assert.NotEmpty(t, etag) {
+		return
+	}
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+	assert.Equal(t, http.StatusNotModified, get("/api/tags/md/items.atom", http.Header{"If-None-Match": {etag}}).Code)
+	assert.Equal(t, http.StatusOK, get("/api/tags/md/items.atom", http.Header{"If-Modified-Since": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}).Code)
+	assert.NotEqual(t, etag, get("/api/tags/md/items.rss", nil).Header().Get("ETag"))
+
+	a2 := model.Item{Slug: "markdown-2", Title: "again", Description: "d", Body: "b", AuthorID: 2, Tags: []model.Tag{{Tag: "md"}}}
+	if assert.NoError(t, as.CreateItem(&a2)) {
+		rec = get("/api/tags/md/items.atom", http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 2, strings.Count(rec.Body.String(), "<entry>"))
+
+		// Deleting an item leaves the newest one, and so the update time,
+		// in place, but the document changes.
+		etag = rec.Header().Get("ETag")
+		if assert.NoError(t, as.DeleteItem(&a)) {
+			rec = get("/api/tags/md/items.atom", http.Header{"If-None-Match": {etag}})
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 1, strings.Count(rec.Body.String(), "<entry>"))
+		}
+	}
+}
@@ -0,0 +1,952 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+// graphQLParams is the body of a GraphQL POST, or the query parameters of
+// a GET.
+type graphQLParams struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLErrorResponse answers requests that fail before execution. Unlike
+// graphql.Result it has no data entry, as the GraphQL spec asks.
+type graphQLErrorResponse struct {
+	Errors []gqlerrors.FormattedError `json:"errors"`
+}
+
+// GraphQL runs a query, or over POST a mutation, against the stores. The
+// response follows the GraphQL spec; requests that fail parsing,
+// validation or the depth and complexity limits answer 400 without data.
+func (h *Handler) GraphQL(c echo.Context) error {
+	var params graphQLParams
+	if c.Request().Method == echo.GET {
+		params.Query = c.QueryParam("query")
+		params.OperationName = c.QueryParam("operationName")
+		if v := c.QueryParam("variables"); v != "" {
+			if err := decodeJSON(strings.NewReader(v), &params.Variables); err != nil {
+				return utils.RenderError(c, http.StatusBadRequest, err)
+			}
+		}
+	} else if err := decodeJSON(c.Request().Body, &params); err != nil {
+		return utils.RenderError(c, http.StatusBadRequest, err)
+	}
+	if err := c.Validate(&params); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(params.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &graphQLErrorResponse{[]gqlerrors.FormattedError{gqlerrors.FormatError(err)}})
+	}
+	schema := graphQLSchema()
+	if res := graphql.ValidateDocument(schema, doc, nil); !res.IsValid {
+		return c.JSON(http.StatusBadRequest, &graphQLErrorResponse{res.Errors})
+	}
+	// Without a single matching operation Execute reports the error.
+	if op := graphQLOperation(doc, params.OperationName); op != nil {
+		if c.Request().Method == echo.GET && op.Operation != ast.OperationTypeQuery {
+			return c.JSON(http.StatusBadRequest, &graphQLErrorResponse{[]gqlerrors.FormattedError{
+				gqlerrors.NewFormattedError("GET requests can only run queries; send " + op.Operation + "s with POST."),
+			}})
+		}
+		if err := checkGraphQLLimits(schema, doc, op, params.Variables); err != nil {
+			return c.JSON(http.StatusBadRequest, &graphQLErrorResponse{[]gqlerrors.FormattedError{gqlerrors.FormatError(err)}})
+		}
+	}
+
+	r := newGraphQLRequest(h, c)
+	res := graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		AST:           doc,
+		OperationName: params.OperationName,
+		Args:          params.Variables,
+		Context:       context.WithValue(c.Request().Context(), graphQLRequestKey{}, r),
+	})
+	return c.JSON(http.StatusOK, res)
+}
+
+// GraphQLSchema serves the schema in the GraphQL schema language, for
+// client code generators that do not run the introspection query.
+func (h *Handler) GraphQLSchema(c echo.Context) error {
+	return c.String(http.StatusOK, printGraphQLSchema(graphQLSchema()))
+}
+
+// decodeJSON decodes a GraphQL body or variables parameter. Numbers stay
+// float64, which graphql-go coerces to Int and Float.
+func decodeJSON(r io.Reader, v interface{}) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed JSON: "+err.Error())
+	}
+	return nil
+}
+
+// graphQLOperation picks the operation a request runs: the one named, or
+// the only one. It returns nil when there is no such operation.
+func graphQLOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+type graphQLRequestKey struct{}
+
+// graphQLRequest is the state resolvers share during one request. The
+// loaders batch the per-viewer flags and comments of every object at one
+// depth of the query into a single store call.
+type graphQLRequest struct {
+	h         *Handler
+	c         echo.Context
+	following *graphQLLoader
+	favorited *graphQLLoader
+	comments  *graphQLLoader
+}
+
+func newGraphQLRequest(h *Handler, c echo.Context) *graphQLRequest {
+	viewer := playerIDFromToken(c)
+	return &graphQLRequest{
+		h: h,
+		c: c,
+		following: newGraphQLLoader(func(ids []uint) (map[uint]interface{}, error) {
+			set, err := h.players(c).FollowingSet(viewer, ids)
+			return boolValues(ids, set), err
+		}),
+		favorited: newGraphQLLoader(func(ids []uint) (map[uint]interface{}, error) {
+			set, err := h.items(c).FavoritedBy(viewer, ids)
+			return boolValues(ids, set), err
+		}),
+		comments: newGraphQLLoader(func(ids []uint) (map[uint]interface{}, error) {
+			comments, err := h.items(c).CommentsByItems(ids)
+			values := make(map[uint]interface{}, len(ids))
+			for _, id := range ids {
+				cs := comments[id]
+				list := make([]*model.Comment, len(cs))
+				for i := range cs {
+					list[i] = &cs[i]
+				}
+				values[id] = list
+			}
+			return values, err
+		}),
+	}
+}
+
+func boolValues(ids []uint, set map[uint]bool) map[uint]interface{} {
+	values := make(map[uint]interface{}, len(ids))
+	for _, id := range ids {
+		values[id] = set[id]
+	}
+	return values
+}
+
+// graphQLLoader collects the IDs resolvers ask for and loads them in one
+// batch when the first of their thunks is forced. graphql-go forces thunks
+// breadth first once every field above them has resolved, so a batch
+// covers one depth of the query. Values are cached, so a loader should
+// live for one request.
+type graphQLLoader struct {
+	batch func(ids []uint) (map[uint]interface{}, error)
+
+	mu      sync.Mutex
+	results map[uint]*graphQLLoaded
+	pending []uint
+}
+
+type graphQLLoaded struct {
+	value interface{}
+	err   error
+}
+
+func newGraphQLLoader(batch func(ids []uint) (map[uint]interface{}, error)) *graphQLLoader {
+	return &graphQLLoader{batch: batch, results: make(map[uint]*graphQLLoaded)}
+}
+
+// Load schedules id for the next batch. Return the thunk from a resolver.
+func (l *graphQLLoader) Load(id uint) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.results[id]; !ok {
+		l.results[id] = nil
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.results[id] == nil {
+			l.dispatch()
+		}
+		r := l.results[id]
+		return r.value, r.err
+	}
+}
+
+// Clear forgets an ID, after a mutation changed its value.
+func (l *graphQLLoader) Clear(id uint) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r, ok := l.results[id]; ok && r != nil {
+		delete(l.results, id)
+	}
+}
+
+func (l *graphQLLoader) dispatch() {
+	ids := l.pending
+	l.pending = nil
+	values, err := l.batch(ids)
+	for _, id := range ids {
+		l.results[id] = &graphQLLoaded{value: values[id], err: err}
+	}
+}
+
+func requestOf(p graphql.ResolveParams) *graphQLRequest {
+	return p.Context.Value(graphQLRequestKey{}).(*graphQLRequest)
+}
+
+func (r *graphQLRequest) viewer() uint {
+	return playerIDFromToken(r.c)
+}
+
+// graphQLError carries an AppError into the errors of a GraphQL response,
+// with its code, and field details for validation errors, as extensions.
+type graphQLError struct {
+	body utils.ErrorBody
+}
+
+func (e *graphQLError) Error() string {
+	return e.body.Message
+}
+
+func (e *graphQLError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.body.Code}
+	if len(e.body.Fields) > 0 {
+		ext["fields"] = e.body.Fields
+	}
+	return ext
+}
+
+// error localizes err the way utils.RenderError would render it.
+func (r *graphQLRequest) error(status int, err error) error {
+	e := utils.AsAppError(err, status)
+	if e.Status >= http.StatusInternalServerError {
+		r.h.log(r.c).Error("internal error", "err", err, "status", e.Status)
+	}
+	lang := utils.Messages.Match(r.c.Request().Header.Get("Accept-Language"))
+	return &graphQLError{e.Localize(lang).Errors}
+}
+
+// authorize checks that an API key used for the request grants scope.
+func (r *graphQLRequest) authorize(scope string) error {
+	if !middleware.HasScope(r.c, scope) {
+		return r.error(http.StatusForbidden, middleware.ErrScopeMissing)
+	}
+	return nil
+}
+
+// login requires a player, as the JWT middleware does on REST routes.
+func (r *graphQLRequest) login() error {
+	if r.viewer() == 0 {
+		return r.error(http.StatusUnauthorized, middleware.ErrJWTMissing)
+	}
+	return nil
+}
+
+// mutate admits a mutation: it needs a player, the scope, and a token
+// from the write rate limit, as the equivalent REST route would.
+func (r *graphQLRequest) mutate(scope string) error {
+	if err := r.login(); err != nil {
+		return err
+	}
+	if err := r.authorize(scope); err != nil {
+		return err
+	}
+	if err := r.h.takeRateLimit(r.c, "write", writeRateLimit); err != nil {
+		return r.error(http.StatusTooManyRequests, err)
+	}
+	return nil
+}
+
+// itemPage is the source of ItemConnection.
+type itemPage struct {
+	Items []*model.Item
+	Count int
+}
+
+func (r *graphQLRequest) listItems(args map[string]interface{}, list func(order item.Order, offset, limit int) ([]model.Item, int, error)) (interface{}, error) {
+	if err := r.authorize(model.ScopeItemsRead); err != nil {
+		return nil, err
+	}
+	order, ok := args["sort"].(item.Order)
+	if !ok {
+		order = item.OrderRecent
+	}
+	offset, _ := args["offset"].(int)
+	limit, ok := args["limit"].(int)
+	if !ok {
+		limit = graphQLPageSize
+	}
+	items, count, err := list(order, offset, limit)
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	page := &itemPage{Items: make([]*model.Item, len(items)), Count: count}
+	for i := range items {
+		page.Items[i] = &items[i]
+	}
+	return page, nil
+}
+
+func stringArg(p graphql.ResolveParams, name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+var (
+	graphQLOnce     sync.Once
+	graphQLCompiled *graphql.Schema
+)
+
+// graphQLSchema builds the schema once. Resolvers find the request they
+// serve in their context.
+func graphQLSchema() *graphql.Schema {
+	graphQLOnce.Do(func() {
+		s, err := newGraphQLSchema()
+		if err != nil {
+			panic(err)
+		}
+		graphQLCompiled = &s
+	})
+	return graphQLCompiled
+}
+
+var (
+	timeScalar = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Time",
+		Description: "An RFC 3339 timestamp.",
+		Serialize: func(v interface{}) interface{} {
+			t, ok := v.(time.Time)
+			if !ok {
+				return nil
+			}
+			return t.Format(time.RFC3339Nano)
+		},
+		ParseValue: func(v interface{}) interface{} {
+			s, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil
+			}
+			return t
+		},
+		ParseLiteral: func(v ast.Value) interface{} {
+			s, ok := v.(*ast.StringValue)
+			if !ok {
+				return nil
+			}
+			t, err := time.Parse(time.RFC3339Nano, s.Value)
+			if err != nil {
+				return nil
+			}
+			return t
+		},
+	})
+
+	itemOrderEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "ItemOrder",
+		Description: "How item lists are sorted. Ties fall back to recency.",
+		Values: graphql.EnumValueConfigMap{
+			"RECENT":    {Value: item.OrderRecent, Description: "Newest first."},
+			"FAVORITES": {Value: item.OrderFavorites, Description: "Most favorited first."},
+			"COMMENTS":  {Value: item.OrderComments, Description: "Most commented first."},
+		},
+	})
+)
+
+func pageArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"sort":   {Type: itemOrderEnum, DefaultValue: item.OrderRecent},
+		"offset": {Type: graphql.Int, DefaultValue: 0, Description: "Items to skip."},
+		"limit":  {Type: graphql.Int, DefaultValue: graphQLPageSize, Description: "Items per page."},
+	}
+}
+
+func nonNullList(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// structField declares a field read from the source struct's field of the
+// same name. Unlike graphql-go's default resolver it finds the fields
+// promoted from gorm.Model.
+func structField(t graphql.Output) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: resolveStructField}
+}
+
+func resolveStructField(p graphql.ResolveParams) (interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(p.Source))
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	f := v.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, p.Info.FieldName) })
+	if !f.IsValid() {
+		return nil, nil
+	}
+	return f.Interface(), nil
+}
+
+func newGraphQLSchema() (graphql.Schema, error) {
+	var (
+		nonNullString = graphql.NewNonNull(graphql.String)
+		nonNullInt    = graphql.NewNonNull(graphql.Int)
+		nonNullTime   = graphql.NewNonNull(timeScalar)
+		profile       *graphql.Object
+		itemType      *graphql.Object
+		comment       *graphql.Object
+	)
+	itemConnection := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ItemConnection",
+		Description: "A page of items and the size of the whole list.",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"items": structField(nonNullList(itemType)),
+				"count": structField(nonNullInt),
+			}
+		}),
+	})
+
+	profile = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Profile",
+		Description: "A player as others see them.",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"username": structField(nonNullString),
+				"bio":      structField(graphql.String),
+				"image":    structField(graphql.String),
+				"following": {Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the logged in player follows them.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestOf(p).following.Load(p.Source.(*model.Player).ID), nil
+					}},
+				"followersCount": structField(nonNullInt),
+				"items": {Type: graphql.NewNonNull(itemConnection), Args: pageArgs(), Description: "Items they wrote.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						r, u := requestOf(p), p.Source.(*model.Player)
+						return r.listItems(p.Args, func(order item.Order, offset, limit int) ([]model.Item, int, error) {
+							return r.h.items(r.c).ListByAuthor(u.Username, order, offset, limit)
+						})
+					}},
+				"favorites": {Type: graphql.NewNonNull(itemConnection), Args: pageArgs(), Description: "Items they favorited.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						r, u := requestOf(p), p.Source.(*model.Player)
+						return r.listItems(p.Args, func(order item.Order, offset, limit int) ([]model.Item, int, error) {
+							return r.h.items(r.c).ListByWhoFavorited(u.Username, order, offset, limit)
+						})
+					}},
+			}
+		}),
+	})
+
+	itemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"slug":        structField(nonNullString),
+				"title":       structField(nonNullString),
+				"description": structField(nonNullString),
+				"body":        structField(nonNullString),
+				"tagList": {Type: nonNullList(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						a := p.Source.(*model.Item)
+						tags := make([]string, 0, len(a.Tags))
+						for _, t := range a.Tags {
+							tags = append(tags, t.Tag)
+						}
+						return tags, nil
+					}},
+				"createdAt": structField(nonNullTime),
+				"updatedAt": structField(nonNullTime),
+				"favorited": {Type: graphql.NewNonNull(graphql.Boolean), Description: "Whether the logged in player favorited it.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestOf(p).favorited.Load(p.Source.(*model.Item).ID), nil
+					}},
+				"favoritesCount": structField(nonNullInt),
+				"commentsCount":  structField(nonNullInt),
+				"author": {Type: graphql.NewNonNull(profile),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return &p.Source.(*model.Item).Author, nil
+					}},
+				"comments": {Type: nonNullList(comment), Description: "Comments, oldest first.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestOf(p).comments.Load(p.Source.(*model.Item).ID), nil
+					}},
+			}
+		}),
+	})
+
+	comment = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Comment",
+		Fields: graphql.Fields{
+			"id":        structField(graphql.NewNonNull(graphql.ID)),
+			"body":      structField(nonNullString),
+			"createdAt": structField(nonNullTime),
+			"updatedAt": structField(nonNullTime),
+			"author": {Type: graphql.NewNonNull(profile),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return &p.Source.(*model.Comment).Player, nil
+				}},
+		},
+	})
+
+	player := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Player",
+		Description: "The logged in player's account.",
+		Fields: graphql.Fields{
+			"username": structField(nonNullString),
+			"email":    structField(nonNullString),
+			"bio":      structField(graphql.String),
+			"image":    structField(graphql.String),
+			"profile": {Type: graphql.NewNonNull(profile),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				}},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"viewer": {Type: player, Description: "The logged in player, null for anonymous requests.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if r.viewer() == 0 {
+						return nil, nil
+					}
+					u, err := r.h.players(r.c).GetByID(r.viewer())
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return u, nil
+				}},
+			"profile": {Type: profile,
+				Args: graphql.FieldConfigArgument{"username": {Type: nonNullString}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					u, err := r.h.players(r.c).GetByUsername(stringArg(p, "username"))
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return u, nil
+				}},
+			"item": {Type: itemType,
+				Args: graphql.FieldConfigArgument{"slug": {Type: nonNullString}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.authorize(model.ScopeItemsRead); err != nil {
+						return nil, err
+					}
+					a, err := r.h.items(r.c).GetBySlug(stringArg(p, "slug"))
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return a, nil
+				}},
+			"items": {Type: graphql.NewNonNull(itemConnection), Description: "Items, filtered by at most one of tag, author and favorited.",
+				Args: withPageArgs(graphql.FieldConfigArgument{
+					"tag":       {Type: graphql.String, Description: "Only items with this tag."},
+					"author":    {Type: graphql.String, Description: "Only items by this player."},
+					"favorited": {Type: graphql.String, Description: "Only items this player favorited."},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					items := r.h.items(r.c)
+					tag, author, favorited := stringArg(p, "tag"), stringArg(p, "author"), stringArg(p, "favorited")
+					return r.listItems(p.Args, func(order item.Order, offset, limit int) ([]model.Item, int, error) {
+						switch {
+						case tag != "":
+							return items.ListByTag(tag, order, offset, limit)
+						case author != "":
+							return items.ListByAuthor(author, order, offset, limit)
+						case favorited != "":
+							return items.ListByWhoFavorited(favorited, order, offset, limit)
+						}
+						return items.List(order, offset, limit)
+					})
+				}},
+			"feed": {Type: graphql.NewNonNull(itemConnection), Description: "Items by the players the logged in player follows.",
+				Args: pageArgs(),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.login(); err != nil {
+						return nil, err
+					}
+					return r.listItems(p.Args, func(order item.Order, offset, limit int) ([]model.Item, int, error) {
+						return r.h.items(r.c).ListFeed(r.viewer(), order, offset, limit)
+					})
+				}},
+			"tags": {Type: nonNullList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					tags, err := r.h.items(r.c).ListTags()
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					names := make([]string, 0, len(tags))
+					for _, t := range tags {
+						names = append(names, t.Tag)
+					}
+					return names, nil
+				}},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: newGraphQLMutation(player, profile, itemType, comment),
+	})
+}
+
+func withPageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	for name, arg := range pageArgs() {
+		args[name] = arg
+	}
+	return args
+}
+
+func newGraphQLMutation(player, profile, itemType, comment *graphql.Object) *graphql.Object {
+	var (
+		nonNullString  = graphql.NewNonNull(graphql.String)
+		nonNullBoolean = graphql.NewNonNull(graphql.Boolean)
+		slugArg        = &graphql.ArgumentConfig{Type: nonNullString}
+		usernameArg    = &graphql.ArgumentConfig{Type: nonNullString}
+	)
+	updatePlayerInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdatePlayerInput",
+		Description: "Fields left out keep their values.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"username": {Type: graphql.String},
+			"email":    {Type: graphql.String},
+			"password": {Type: graphql.String},
+			"bio":      {Type: graphql.String},
+			"image":    {Type: graphql.String},
+		},
+	})
+	createItemInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateItemInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: nonNullString},
+			"description": {Type: nonNullString},
+			"body":        {Type: nonNullString},
+			"tagList":     {Type: graphql.NewList(nonNullString)},
+		},
+	})
+	updateItemInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdateItemInput",
+		Description: "Fields left out keep their values; tagList replaces all tags.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"body":        {Type: graphql.String},
+			"tagList":     {Type: graphql.NewList(nonNullString)},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"updatePlayer": {Type: graphql.NewNonNull(player),
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(updatePlayerInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeProfileWrite); err != nil {
+						return nil, err
+					}
+					u, err := r.h.players(r.c).GetByID(r.viewer())
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					if u == nil {
+						return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+					}
+					req := newPlayerUpdateRequest()
+					req.populate(u)
+					in := p.Args["input"].(map[string]interface{})
+					setString(in, "username", &req.Player.Username)
+					setString(in, "email", &req.Player.Email)
+					setString(in, "password", &req.Player.Password)
+					setString(in, "bio", &req.Player.Bio)
+					setString(in, "image", &req.Player.Image)
+					if err := req.apply(r.c, u); err != nil {
+						return nil, r.error(http.StatusUnprocessableEntity, err)
+					}
+					if err := r.h.players(r.c).Update(u); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return u, nil
+				}},
+			"followProfile": {Type: graphql.NewNonNull(profile), Args: graphql.FieldConfigArgument{"username": usernameArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return follow(p, true)
+				}},
+			"unfollowProfile": {Type: graphql.NewNonNull(profile), Args: graphql.FieldConfigArgument{"username": usernameArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return follow(p, false)
+				}},
+			"createItem": {Type: graphql.NewNonNull(itemType),
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createItemInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeItemsWrite); err != nil {
+						return nil, err
+					}
+					if err := r.h.takeRateLimit(r.c, "create", createRateLimit); err != nil {
+						return nil, r.error(http.StatusTooManyRequests, err)
+					}
+					req := &itemCreateRequest{}
+					in := p.Args["input"].(map[string]interface{})
+					setString(in, "title", &req.Items.Title)
+					setString(in, "description", &req.Items.Description)
+					setString(in, "body", &req.Items.Body)
+					setStrings(in, "tagList", &req.Items.Tags)
+					var a model.Item
+					if err := req.apply(r.c, &a); err != nil {
+						return nil, r.error(http.StatusUnprocessableEntity, err)
+					}
+					a.AuthorID = r.viewer()
+					if err := r.h.items(r.c).CreateItem(&a); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					itemsCreated.Inc()
+					return &a, nil
+				}},
+			"updateItem": {Type: graphql.NewNonNull(itemType),
+				Args: graphql.FieldConfigArgument{"slug": slugArg, "input": {Type: graphql.NewNonNull(updateItemInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeItemsWrite); err != nil {
+						return nil, err
+					}
+					a, err := r.ownItem(stringArg(p, "slug"))
+					if err != nil {
+						return nil, err
+					}
+					req := &itemUpdateRequest{}
+					req.populate(a)
+					in := p.Args["input"].(map[string]interface{})
+					setString(in, "title", &req.Items.Title)
+					setString(in, "description", &req.Items.Description)
+					setString(in, "body", &req.Items.Body)
+					setStrings(in, "tagList", &req.Items.Tags)
+					if err := req.apply(r.c, a); err != nil {
+						return nil, r.error(http.StatusUnprocessableEntity, err)
+					}
+					if err := r.h.items(r.c).UpdateItem(a, req.Items.Tags); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return a, nil
+				}},
+			"deleteItem": {Type: nonNullBoolean, Args: graphql.FieldConfigArgument{"slug": slugArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeItemsWrite); err != nil {
+						return nil, err
+					}
+					a, err := r.ownItem(stringArg(p, "slug"))
+					if err != nil {
+						return nil, err
+					}
+					if err := r.h.items(r.c).DeleteItem(a); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					return true, nil
+				}},
+			"favoriteItem": {Type: graphql.NewNonNull(itemType), Args: graphql.FieldConfigArgument{"slug": slugArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return favorite(p, true)
+				}},
+			"unfavoriteItem": {Type: graphql.NewNonNull(itemType), Args: graphql.FieldConfigArgument{"slug": slugArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return favorite(p, false)
+				}},
+			"addComment": {Type: graphql.NewNonNull(comment),
+				Args: graphql.FieldConfigArgument{"slug": slugArg, "body": {Type: nonNullString}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeCommentsWrite); err != nil {
+						return nil, err
+					}
+					a, err := r.item(stringArg(p, "slug"))
+					if err != nil {
+						return nil, err
+					}
+					req := &createCommentRequest{}
+					req.Comment.Body = stringArg(p, "body")
+					var cm model.Comment
+					if err := req.apply(r.c, &cm); err != nil {
+						return nil, r.error(http.StatusUnprocessableEntity, err)
+					}
+					if err := r.h.items(r.c).AddComment(a, &cm); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					commentsPosted.Inc()
+					r.comments.Clear(a.ID)
+					return &cm, nil
+				}},
+			"deleteComment": {Type: nonNullBoolean,
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := requestOf(p)
+					if err := r.mutate(model.ScopeCommentsWrite); err != nil {
+						return nil, err
+					}
+					id, err := strconv.ParseUint(stringArg(p, "id"), 10, 32)
+					if err != nil {
+						return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+					}
+					cm, err := r.h.items(r.c).GetCommentByID(uint(id))
+					if err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					if cm == nil {
+						return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+					}
+					if cm.PlayerID != r.viewer() {
+						return nil, r.error(http.StatusForbidden, utils.ErrAccessForbidden())
+					}
+					if err := r.h.items(r.c).DeleteComment(cm); err != nil {
+						return nil, r.error(http.StatusInternalServerError, err)
+					}
+					r.comments.Clear(cm.ItemID)
+					return true, nil
+				}},
+		},
+	})
+}
+
+func follow(p graphql.ResolveParams, add bool) (interface{}, error) {
+	r := requestOf(p)
+	if err := r.mutate(model.ScopeProfileWrite); err != nil {
+		return nil, err
+	}
+	u, err := r.h.players(r.c).GetByUsername(stringArg(p, "username"))
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+	}
+	if add {
+		err = r.h.players(r.c).AddFollower(u, r.viewer())
+	} else {
+		err = r.h.players(r.c).RemoveFollower(u, r.viewer())
+	}
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	r.following.Clear(u.ID)
+	return u, nil
+}
+
+func favorite(p graphql.ResolveParams, add bool) (interface{}, error) {
+	r := requestOf(p)
+	if err := r.mutate(model.ScopeItemsWrite); err != nil {
+		return nil, err
+	}
+	a, err := r.item(stringArg(p, "slug"))
+	if err != nil {
+		return nil, err
+	}
+	if add {
+		err = r.h.items(r.c).AddFavorite(a, r.viewer())
+	} else {
+		err = r.h.items(r.c).RemoveFavorite(a, r.viewer())
+	}
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	r.favorited.Clear(a.ID)
+	return a, nil
+}
+
+// item looks up an item by slug, failing with not found.
+func (r *graphQLRequest) item(slug string) (*model.Item, error) {
+	a, err := r.h.items(r.c).GetBySlug(slug)
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	if a == nil {
+		return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+	}
+	return a, nil
+}
+
+// ownItem looks up an item of the logged in player by slug.
+func (r *graphQLRequest) ownItem(slug string) (*model.Item, error) {
+	a, err := r.h.items(r.c).GetPlayerItemBySlug(r.viewer(), slug)
+	if err != nil {
+		return nil, r.error(http.StatusInternalServerError, err)
+	}
+	if a == nil {
+		return nil, r.error(http.StatusNotFound, utils.ErrNotFound())
+	}
+	return a, nil
+}
+
+// setString copies an input field into a request when the client gave
+// it. graphql-go leaves out null fields, so like null in a REST body they
+// keep the value.
+func setString(in map[string]interface{}, name string, dst *string) {
+	if v, ok := in[name]; ok {
+		*dst, _ = v.(string)
+	}
+}
+
+func setStrings(in map[string]interface{}, name string, dst *[]string) {
+	v, ok := in[name]
+	if !ok {
+		return
+	}
+	list, _ := v.([]interface{})
+	*dst = make([]string, len(list))
+	for i, s := range list {
+		(*dst)[i] = fmt.Sprint(s)
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// graphQLMaxDepth bounds how deeply queries nest, so that one request
+	// cannot walk item → author → items → ... indefinitely.
+	graphQLMaxDepth = 10
+	// graphQLMaxComplexity bounds the fields a query may resolve, counting
+	// the fields beneath a page once per item it may hold.
+	graphQLMaxComplexity = 1000
+	// graphQLPageSize is the limit of item lists that do not give one.
+	graphQLPageSize = 20
+)
+
+// checkGraphQLLimits rejects operations nested deeper than
+// graphQLMaxDepth or costing more than graphQLMaxComplexity. It runs on
+// validated documents, whose fields and fragments exist and whose
+// fragments do not form cycles.
+func checkGraphQLLimits(schema *graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) error {
+	w := &graphQLCost{schema: schema, fragments: make(map[string]*ast.FragmentDefinition), variables: make(map[string]interface{})}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[f.Name.Value] = f
+		}
+	}
+	for _, v := range op.VariableDefinitions {
+		if v.DefaultValue != nil {
+			w.variables[v.Variable.Name.Value] = v.DefaultValue
+		}
+	}
+	for name, v := range variables {
+		w.variables[name] = v
+	}
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	depth, cost := w.selections(root, op.SelectionSet)
+	if depth > graphQLMaxDepth {
+		return fmt.Errorf("Query is nested deeper than the limit of %d.", graphQLMaxDepth)
+	}
+	if cost > graphQLMaxComplexity {
+		return fmt.Errorf("Query complexity %d exceeds the limit of %d.", cost, graphQLMaxComplexity)
+	}
+	return nil
+}
+
+// graphQLCost measures the depth and complexity of selections. Every field
+// costs one, and the selections of a field with a limit argument count
+// once per item of the page.
+type graphQLCost struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func (w *graphQLCost) selections(parent graphql.Type, set *ast.SelectionSet) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, s := range set.Selections {
+		var d, c int
+		switch s := s.(type) {
+		case *ast.Field:
+			d, c = w.field(parent, s)
+		case *ast.InlineFragment:
+			d, c = w.selections(w.typeCondition(parent, s.TypeCondition), s.SelectionSet)
+		case *ast.FragmentSpread:
+			if f := w.fragments[s.Name.Value]; f != nil {
+				d, c = w.selections(w.typeCondition(parent, f.TypeCondition), f.SelectionSet)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+		cost = saturatingAdd(cost, c)
+	}
+	return depth, cost
+}
+
+func (w *graphQLCost) typeCondition(parent graphql.Type, cond *ast.Named) graphql.Type {
+	if cond == nil {
+		return parent
+	}
+	return w.schema.Type(cond.Name.Value)
+}
+
+func (w *graphQLCost) field(parent graphql.Type, f *ast.Field) (depth, cost int) {
+	name := f.Name.Value
+	def := fieldDefinition(parent, name)
+	if def == nil {
+		return 1, 1
+	}
+	child, _ := graphql.GetNamed(def.Type).(graphql.Type)
+	depth, cost = w.selections(child, f.SelectionSet)
+	switch {
+	case name == "ofType":
+		// Introspection nests ofType once per list or non-null wrapper, a
+		// few levels that each resolve one type, so they do not count
+		// towards the depth and the standard introspection query fits.
+		return depth, saturatingAdd(1, cost)
+	case strings.HasPrefix(name, "__"):
+		return depth + 1, saturatingAdd(1, cost)
+	}
+	return depth + 1, saturatingAdd(1, saturatingMul(w.limit(def, f), cost))
+}
+
+// fieldDefinition looks a field up on its parent type, including the
+// introspection fields every query type has.
+func fieldDefinition(parent graphql.Type, name string) *graphql.FieldDefinition {
+	switch name {
+	case graphql.SchemaMetaFieldDef.Name:
+		return graphql.SchemaMetaFieldDef
+	case graphql.TypeMetaFieldDef.Name:
+		return graphql.TypeMetaFieldDef
+	case graphql.TypeNameMetaFieldDef.Name:
+		return graphql.TypeNameMetaFieldDef
+	}
+	switch t := parent.(type) {
+	case *graphql.Object:
+		return t.Fields()[name]
+	case *graphql.Interface:
+		return t.Fields()[name]
+	}
+	return nil
+}
+
+// limit returns how many items a field's page may hold: the value of its
+// limit argument, or 1 for fields without one. Negative limits lift the
+// limit in the store, so they cost more than the whole budget.
+func (w *graphQLCost) limit(def *graphql.FieldDefinition, f *ast.Field) int {
+	var arg *graphql.Argument
+	for _, a := range def.Args {
+		if a.Name() == "limit" {
+			arg = a
+		}
+	}
+	if arg == nil {
+		return 1
+	}
+	n, _ := arg.DefaultValue.(int)
+	for _, a := range f.Arguments {
+		if a.Name.Value != "limit" {
+			continue
+		}
+		var v interface{} = a.Value
+		if ref, ok := v.(*ast.Variable); ok {
+			if v, ok = w.variables[ref.Name.Value]; !ok {
+				break
+			}
+		}
+		switch v := v.(type) {
+		case *ast.IntValue:
+			n, _ = strconv.Atoi(v.Value)
+		case float64:
+			n = int(math.Max(math.Min(v, math.MaxInt32), -1))
+		}
+	}
+	if n < 0 || n > graphQLMaxComplexity {
+		return graphQLMaxComplexity + 1
+	}
+	return n
+}
+
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
+
+func saturatingMul(a, b int) int {
+	if a != 0 && b > math.MaxInt32/a {
+		return math.MaxInt32
+	}
+	return a * b
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// printGraphQLSchema writes the schema in the GraphQL schema language:
+// the query and mutation types, then the other types by name. graphql-go
+// keeps fields in maps, so fields, arguments and enum values are sorted
+// by name too.
+func printGraphQLSchema(s *graphql.Schema) string {
+	var b strings.Builder
+	printGraphQLType(&b, s.QueryType())
+	if s.MutationType() != nil {
+		b.WriteString("\n")
+		printGraphQLType(&b, s.MutationType())
+	}
+	types := s.TypeMap()
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := types[name]
+		if t == s.QueryType() || t == s.MutationType() || isBuiltinGraphQLType(name) {
+			continue
+		}
+		b.WriteString("\n")
+		printGraphQLType(&b, t)
+	}
+	return b.String()
+}
+
+func isBuiltinGraphQLType(name string) bool {
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	}
+	return strings.HasPrefix(name, "__")
+}
+
+func printGraphQLType(b *strings.Builder, t graphql.Type) {
+	switch t := t.(type) {
+	case *graphql.Scalar:
+		printGraphQLDescription(b, "", t.Description())
+		fmt.Fprintf(b, "scalar %s\n", t.Name())
+	case *graphql.Enum:
+		printGraphQLDescription(b, "", t.Description())
+		fmt.Fprintf(b, "enum %s {\n", t.Name())
+		values := t.Values()
+		sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+		for _, v := range values {
+			printGraphQLDescription(b, "  ", v.Description)
+			fmt.Fprintf(b, "  %s\n", v.Name)
+		}
+		b.WriteString("}\n")
+	case *graphql.Object:
+		printGraphQLDescription(b, "", t.Description())
+		fmt.Fprintf(b, "type %s {\n", t.Name())
+		fields := t.Fields()
+		for _, name := range sortedKeys(fields) {
+			f := fields[name]
+			printGraphQLDescription(b, "  ", f.Description)
+			fmt.Fprintf(b, "  %s%s: %s", f.Name, printGraphQLArgs(f.Args), f.Type)
+			if f.DeprecationReason != "" {
+				fmt.Fprintf(b, " @deprecated(reason: %s)", strconv.Quote(f.DeprecationReason))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+	case *graphql.InputObject:
+		printGraphQLDescription(b, "", t.Description())
+		fmt.Fprintf(b, "input %s {\n", t.Name())
+		fields := t.Fields()
+		for _, name := range sortedKeys(fields) {
+			f := fields[name]
+			printGraphQLDescription(b, "  ", f.Description())
+			fmt.Fprintf(b, "  %s\n", printGraphQLInputValue(f.Name(), f.Type, f.DefaultValue))
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printGraphQLDescription(b *strings.Builder, indent, d string) {
+	if d == "" {
+		return
+	}
+	if !strings.Contains(d, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(d))
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(d, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.Replace(line, `"""`, `\"""`, -1))
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+func printGraphQLArgs(args []*graphql.Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+	sorted := append([]*graphql.Argument(nil), args...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+	s := make([]string, len(sorted))
+	for i, a := range sorted {
+		s[i] = printGraphQLInputValue(a.Name(), a.Type, a.DefaultValue)
+	}
+	return "(" + strings.Join(s, ", ") + ")"
+}
+
+func printGraphQLInputValue(name string, t graphql.Input, def interface{}) string {
+	s := name + ": " + t.String()
+	if def != nil {
+		s += " = " + printGraphQLLiteral(t, def)
+	}
+	return s
+}
+
+// printGraphQLLiteral writes a Go input value back as a GraphQL literal.
+func printGraphQLLiteral(t graphql.Type, v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		return printGraphQLLiteral(t.OfType, v)
+	case *graphql.List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return printGraphQLLiteral(t.OfType, v)
+		}
+		s := make([]string, rv.Len())
+		for i := range s {
+			s[i] = printGraphQLLiteral(t.OfType, rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(s, ", ") + "]"
+	case *graphql.Enum:
+		return fmt.Sprint(t.Serialize(v))
+	}
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+)
+
+// countingItemStore counts the calls GraphQL should batch.
+type countingItemStore struct {
+	item.Store
+	favoritedBy, commentsByItems int
+}
+
+func (s *countingItemStore) FavoritedBy(playerID uint, itemIDs []uint) (map[uint]bool, error) {
+	s.favoritedBy++
+	return s.Store.FavoritedBy(playerID, itemIDs)
+}
+
+func (s *countingItemStore) CommentsByItems(itemIDs []uint) (map[uint][]model.Comment, error) {
+	s.commentsByItems++
+	return s.Store.CommentsByItems(itemIDs)
+}
+
+type graphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func graphQLRequestTo(e *echo.Echo, token, body string) (int, graphQLResponse) {
+	req := httptest.NewRequest(echo.POST, "/api/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, authHeader(token))
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var res graphQLResponse
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec.Code, res
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	tearDown()
+	setup()
+	items := &countingItemStore{Store: as}
+	h := NewHandler(us, items)
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	query := `{"query":"{ items(sort: RECENT) { count items { slug favorited tagList author { username following } comments { body author { username } } } } }"}`
+	code, res := graphQLRequestTo(e, utils.GenerateJWT(1), query)
+	if !assert.Equal(t, http.StatusOK, code) || !assert.Empty(t, res.Errors) {
+		return
+	}
+	conn := res.Data["items"].(map[string]interface{})
+	assert.Equal(t, float64(2), conn["count"])
+	list := conn["items"].([]interface{})
+	if assert.Len(t, list, 2) {
+		item2 := list[0].(map[string]interface{})
+		assert.Equal(t, "item2-slug", item2["slug"])
+		assert.Equal(t, true, item2["favorited"])
+		assert.Equal(t, map[string]interface{}{"username": "player2", "following": true}, item2["author"])
+		comments := item2["comments"].([]interface{})
+		if assert.Len(t, comments, 1) {
+			assert.Equal(t, "item2 comment1 by player1", comments[0].(map[string]interface{})["body"])
+		}
+		item1 := list[1].(map[string]interface{})
+		assert.Equal(t, false, item1["favorited"])
+		assert.Equal(t, []interface{}{"tag1", "tag2"}, item1["tagList"])
+	}
+	// One store call per loader, however many items were listed.
+	assert.Equal(t, 1, items.favoritedBy)
+	assert.Equal(t, 1, items.commentsByItems)
+
+	code, res = graphQLRequestTo(e, "", `{"query":"query($slug: String!) { item(slug: $slug) { title favorited } viewer { username } }","variables":{"slug":"item1-slug"}}`)
+	if assert.Equal(t, http.StatusOK, code) {
+		assert.Equal(t, map[string]interface{}{"title": "item1 title", "favorited": false}, res.Data["item"])
+		assert.Nil(t, res.Data["viewer"])
+	}
+
+	req := httptest.NewRequest(echo.GET, "/api/graphql?query="+url.QueryEscape(`{ tags }`), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"tag1"`)
+}
+
+func TestGraphQLMutation(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	mutation := `{"query":"mutation { createItem(input: {title: \"graph item\", description: \"d\", body: \"b\", tagList: [\"gql\"]}) { slug author { username } } }"}`
+	code, res := graphQLRequestTo(e, "", mutation)
+	if assert.Equal(t, http.StatusOK, code) && assert.Len(t, res.Errors, 1) {
+		assert.Nil(t, res.Data["createItem"])
+		assert.Equal(t, utils.CodeUnauthorized, res.Errors[0].Extensions["code"])
+		assert.Equal(t, []interface{}{"createItem"}, res.Errors[0].Path)
+	}
+
+	code, res = graphQLRequestTo(e, utils.GenerateJWT(1), mutation)
+	if assert.Equal(t, http.StatusOK, code) && assert.Empty(t, res.Errors) {
+		assert.Equal(t, map[string]interface{}{"slug": "graph-item", "author": map[string]interface{}{"username": "player1"}}, res.Data["createItem"])
+	}
+
+	code, res = graphQLRequestTo(e, utils.GenerateJWT(2), `{"query":"mutation { favoriteItem(slug: \"graph-item\") { favorited favoritesCount } }"}`)
+	if assert.Equal(t, http.StatusOK, code) && assert.Empty(t, res.Errors) {
+		assert.Equal(t, map[string]interface{}{"favorited": true, "favoritesCount": float64(1)}, res.Data["favoriteItem"])
+	}
+
+	code, res = graphQLRequestTo(e, utils.GenerateJWT(2), `{"query":"mutation { deleteItem(slug: \"graph-item\") }"}`)
+	if assert.Equal(t, http.StatusOK, code) && assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, utils.CodeNotFound, res.Errors[0].Extensions["code"])
+	}
+
+	req := httptest.NewRequest(echo.GET, "/api/graphql?query="+url.QueryEscape(`mutation { deleteItem(slug: "graph-item") }`), nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"data"`)
+}
+
+func TestGraphQLInvalid(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	code, res := graphQLRequestTo(e, "", `{"query":"{ items { nope } item { slug } }"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Nil(t, res.Data)
+	if assert.Len(t, res.Errors, 2) {
+		assert.Contains(t, res.Errors[0].Message, `"nope"`)
+		assert.Contains(t, res.Errors[1].Message, `"slug"`)
+	}
+
+	code, res = graphQLRequestTo(e, "", `{"query":"{ items { items { author { items { items { author { items { items { author { items { items { slug } } } } } } } } } } } }"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, "nested deeper")
+	}
+
+	code, _ = graphQLRequestTo(e, "", `{"query":"{ tags"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	req := httptest.NewRequest(echo.GET, "/api/graphql/schema", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "type Query {"))
+	assert.Contains(t, rec.Body.String(), "createItem(input: CreateItemInput!): Item!")
+}
+
+// introspectionQuery is the query GraphiQL and code generators send.
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) {
+    name description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue {
+  name description
+  type { ...TypeRef }
+  defaultValue
+}
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } }
+}`
+
+func TestGraphQLIntrospection(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	body, _ := json.Marshal(map[string]string{"query": introspectionQuery})
+	code, res := graphQLRequestTo(e, "", string(body))
+	if !assert.Equal(t, http.StatusOK, code) || !assert.Empty(t, res.Errors) {
+		return
+	}
+	schema := res.Data["__schema"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "Query"}, schema["queryType"])
+	assert.Equal(t, map[string]interface{}{"name": "Mutation"}, schema["mutationType"])
+	types := make(map[string]map[string]interface{})
+	for _, v := range schema["types"].([]interface{}) {
+		typ := v.(map[string]interface{})
+		types[typ["name"].(string)] = typ
+	}
+	for _, name := range []string{"Item", "Profile", "Comment", "ItemConnection", "ItemOrder", "Time", "CreateItemInput"} {
+		assert.Contains(t, types, name)
+	}
+	if item, ok := types["Item"]; assert.True(t, ok) {
+		for _, f := range item["fields"].([]interface{}) {
+			f := f.(map[string]interface{})
+			if f["name"] == "tagList" {
+				// [String!]! unwraps as NON_NULL, LIST, NON_NULL, SCALAR.
+				ref := f["type"].(map[string]interface{})
+				var kinds []interface{}
+				for ref != nil {
+					kinds = append(kinds, ref["kind"])
+					ref, _ = ref["ofType"].(map[string]interface{})
+				}
+				assert.Equal(t, []interface{}{"NON_NULL", "LIST", "NON_NULL", "SCALAR"}, kinds)
+			}
+		}
+	}
+
+	code, res = graphQLRequestTo(e, "", `{"query":"{ __type(name: \"ItemOrder\") { kind enumValues { name } } }"}`)
+	if assert.Equal(t, http.StatusOK, code) && assert.Empty(t, res.Errors) {
+		typ := res.Data["__type"].(map[string]interface{})
+		assert.Equal(t, "ENUM", typ["kind"])
+		assert.Len(t, typ["enumValues"], 3)
+	}
+
+	code, res = graphQLRequestTo(e, "", `{"query":"{ items(limit: 1) { __typename items { __typename slug } } }"}`)
+	if assert.Equal(t, http.StatusOK, code) && assert.Empty(t, res.Errors) {
+		conn := res.Data["items"].(map[string]interface{})
+		assert.Equal(t, "ItemConnection", conn["__typename"])
+		assert.Equal(t, "Item", conn["items"].([]interface{})[0].(map[string]interface{})["__typename"])
+	}
+
+	// Introspection still counts towards the depth limit, except for ofType.
+	code, res = graphQLRequestTo(e, "", `{"query":"{ __schema { types { fields { type { fields { type { fields { type { fields { type { fields { name } } } } } } } } } } } }"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, "nested deeper")
+	}
+}
+
+func TestGraphQLComplexity(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	// Each page multiplies the fields beneath it by its limit.
+	nested := `{ items(limit: 50) { items { author { items(limit: 50) { items { slug } } } } } }`
+	body, _ := json.Marshal(map[string]string{"query": nested})
+	code, res := graphQLRequestTo(e, "", string(body))
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Nil(t, res.Data)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, "complexity")
+	}
+
+	// Limits given as variables, or their defaults, count as well.
+	query := `query($n: Int = 5000) { items(limit: $n) { items { slug title } } }`
+	for _, vars := range []map[string]interface{}{nil, {"n": 5000}, {"n": -1}} {
+		body, _ = json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+		code, res = graphQLRequestTo(e, "", string(body))
+		assert.Equal(t, http.StatusBadRequest, code, "variables %v", vars)
+	}
+	body, _ = json.Marshal(map[string]interface{}{"query": query, "variables": map[string]interface{}{"n": 1}})
+	code, res = graphQLRequestTo(e, "", string(body))
+	if assert.Equal(t, http.StatusOK, code) && assert.Empty(t, res.Errors) {
+		assert.Len(t, res.Data["items"].(map[string]interface{})["items"], 1)
+	}
+
+	// Fragments are counted where they are spread.
+	body, _ = json.Marshal(map[string]string{"query": `{ items(limit: 500) { ...page } } fragment page on ItemConnection { items { slug title body } }`})
+	code, _ = graphQLRequestTo(e, "", string(body))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -1,18 +1,72 @@
 package handler
 
 import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
 	"golang-starter-pack/item"
+	"golang-starter-pack/logging"
 	"golang-starter-pack/player"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
 )
 
 type Handler struct {
 	playerStore player.Store
 	itemStore   item.Store
+	loginPolicy utils.LoginPolicy
+	rateLimits  middleware.RateLimitStore
+	logger      *logging.Logger
+	readiness   []readinessCheck
+	draining    int32
+	apiVersions []middleware.APIVersion
+	publicURL   string
 }
 
+// defaultPublicURL is where the server listens unless configured.
+const defaultPublicURL = "http://127.0.0.1:8585"
+
 func NewHandler(us player.Store, as item.Store) *Handler {
 	return &Handler{
 		playerStore: us,
 		itemStore:   as,
+		loginPolicy: utils.DefaultLoginPolicy,
+		rateLimits:  middleware.NewMemoryRateLimitStore(),
+		logger:      logging.Default,
+		apiVersions: newAPIVersions(),
+		publicURL:   defaultPublicURL,
 	}
 }
+
+// SetPublicURL sets the scheme and host clients reach the server at, e.g.
+// "https://api.example.com", for the absolute links of feeds. Links are
+// never built from the request's Host header, which clients control.
+func (h *Handler) SetPublicURL(u string) {
+	h.publicURL = strings.TrimSuffix(u, "/")
+}
+
+// SetLogger replaces the logger, logging.Default unless set.
+func (h *Handler) SetLogger(l *logging.Logger) {
+	h.logger = l
+}
+
+// log returns the logger carrying the request's ID and player.
+func (h *Handler) log(c echo.Context) *logging.Logger {
+	return h.logger.Ctx(c.Request().Context())
+}
+
+// SetRateLimitStore replaces the in-memory rate limit buckets, e.g. with a
+// store shared by all instances. It must be called before Register.
+func (h *Handler) SetRateLimitStore(s middleware.RateLimitStore) {
+	h.rateLimits = s
+}
+
+// players returns the player store traced as part of the request.
+func (h *Handler) players(c echo.Context) player.Store {
+	return player.WithTracing(c.Request().Context(), h.playerStore)
+}
+
+// items returns the item store traced as part of the request.
+func (h *Handler) items(c echo.Context) item.Store {
+	return item.WithTracing(c.Request().Context(), h.itemStore)
+}
@@ -8,10 +8,10 @@ import (
 	"encoding/json"
 
 	"golang-starter-pack/db"
-	"golang-starter-pack/itemem"
+	"golang-starter-pack/item"
 	"golang-starter-pack/model"
 	"golang-starter-pack/player"
-	"golang-starter-pack/rouoetr"
+	"golang-starter-pack/router"
 	"golang-starter-pack/store"
 
 	"github.com/jinzhu/gorm"
@@ -106,9 +106,9 @@ func loadFixtures() error {
 	}
 	as.CreateItem(&a)
 	as.AddComment(&a, &model.Comment{
-		Body:     item1 "comment1",
-		ItemID:     1,
-		PlayerID:
+		Body:     "item1 comment1",
+		ItemID:   1,
+		PlayerID: 1,
 	})
 
 	a2 := model.Item{
@@ -117,9 +117,6 @@ func loadFixtures() error {
 		Description: "item2 description",
 		Body:        "item2 body",
 		AuthorID:    2,
-		Favorites: []model.Player{
-			u1,
-		},
 		Tags: []model.Tag{
 			{
 				Tag: "tag1",
@@ -128,9 +125,9 @@ func loadFixtures() error {
 	}
 	as.CreateItem(&a2)
 	as.AddComment(&a2, &model.Comment{
-		Body:     item2 comment1 by player1",
-		ItemID:     2,
-		PlayerID:
+		Body:     "item2 comment1 by player1",
+		ItemID:   2,
+		PlayerID: 1,
 	})
 	as.AddFavorite(&a2, 1)
 
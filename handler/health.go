@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/version"
+)
+
+// ReadinessCheck reports why the service cannot take traffic, or nil.
+type ReadinessCheck func() error
+
+type readinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// AddReadinessCheck makes /readyz fail while check does.
+func (h *Handler) AddReadinessCheck(name string, check ReadinessCheck) {
+	h.readiness = append(h.readiness, readinessCheck{name, check})
+}
+
+// Drain makes /readyz fail so load balancers stop sending new requests
+// before the server shuts down.
+func (h *Handler) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Healthz reports that the process is up. Like Readyz and Version it is
+// mounted outside the API group, so probes skip auth and rate limits.
+func (h *Handler) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readyz runs every readiness check and answers 503 if any fails or the
+// server is draining. Probes are unauthenticated, so the errors of failing
+// checks are logged rather than returned.
+func (h *Handler) Readyz(c echo.Context) error {
+	status := http.StatusOK
+	checks := make(map[string]string, len(h.readiness)+1)
+	if atomic.LoadInt32(&h.draining) == 1 {
+		status = http.StatusServiceUnavailable
+		checks["draining"] = "shutting down"
+	}
+	for _, rc := range h.readiness {
+		if err := rc.check(); err != nil {
+			status = http.StatusServiceUnavailable
+			checks[rc.name] = "failing"
+			h.log(c).Warn("readiness check failed", "check", rc.name, "err", err)
+		} else {
+			checks[rc.name] = "ok"
+		}
+	}
+	res := map[string]interface{}{"status": "ok", "checks": checks}
+	if status != http.StatusOK {
+		res["status"] = "unavailable"
+	}
+	return c.JSON(status, res)
+}
+
+// Version reports the commit, build time and Go version of the binary.
+func (h *Handler) Version(c echo.Context) error {
+	return c.JSON(http.StatusOK, version.Get())
+}
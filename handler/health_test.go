@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/db"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router"
+	"golang-starter-pack/version"
+)
+
+func TestHealthCaseProbes(t *testing.T) {
+	tearDown()
+	setup()
+	h.AddReadinessCheck("database", d.DB().Ping)
+	h.AddReadinessCheck("migrations", func() error {
+		if pending := db.PendingMigrations(d); len(pending) > 0 {
+			return errors.New(pending[0])
+		}
+		return nil
+	})
+	e.GET(router.HealthzPath, h.Healthz)
+	e.GET(router.ReadyzPath, h.Readyz)
+	e.GET(router.VersionPath, h.Version)
+	probe := func(path string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, path, nil))
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &m))
+		return rec.Code, m
+	}
+
+	code, _ := probe(router.HealthzPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, m := probe(router.VersionPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, version.Commit, m["commit"])
+	assert.NotEmpty(t, m["goVersion"])
+
+	code, m = probe(router.ReadyzPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"database": "ok", "migrations": "ok"}, m["checks"])
+
+	d.DropTable(&model.Tag{})
+	code, m = probe(router.ReadyzPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "failing", m["checks"].(map[string]interface{})["migrations"])
+
+	db.AutoMigrate(d)
+	h.Drain()
+	code, m = probe(router.ReadyzPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", m["status"])
+	assert.Equal(t, "shutting down", m["checks"].(map[string]interface{})["draining"])
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,20 +11,31 @@ import (
 
 func (h *Handler) GetItem(c echo.Context) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetBySlug(slug)
+	a, err := h.items(c).GetBySlug(slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	return c.JSON(http.StatusOK, newItemResponse(c, a))
+	r, etag, err := h.itemRepresentation(c, a)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if utils.NotModified(c, etag) {
+		return nil
+	}
+	return c.JSON(http.StatusOK, r)
 }
 
 func (h *Handler) Items(c echo.Context) error {
 	tag := c.QueryParam("tag")
 	author := c.QueryParam("author")
 	favoritedBy := c.QueryParam("favorited")
+	order, err := listOrder(c)
+	if err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
 	offset, err := strconv.Atoi(c.QueryParam("offset"))
 	if err != nil {
 		offset = 0
@@ -37,32 +47,36 @@ func (h *Handler) Items(c echo.Context) error {
 	var items []model.Item
 	var count int
 	if tag != "" {
-		items, count, err = h.itemStore.ListByTag(tag, offset, limit)
+		items, count, err = h.items(c).ListByTag(tag, order, offset, limit)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return utils.RenderError(c, http.StatusInternalServerError, err)
 		}
 	} else if author != "" {
-		items, count, err = h.itemStore.ListByAuthor(author, offset, limit)
+		items, count, err = h.items(c).ListByAuthor(author, order, offset, limit)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return utils.RenderError(c, http.StatusInternalServerError, err)
 		}
 	} else if favoritedBy != "" {
-		items, count, err = h.itemStore.ListByWhoFavorited(favoritedBy, offset, limit)
+		items, count, err = h.items(c).ListByWhoFavorited(favoritedBy, order, offset, limit)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return utils.RenderError(c, http.StatusInternalServerError, err)
 		}
 	} else {
-		items, count, err = h.itemStore.List(offset, limit)
+		items, count, err = h.items(c).List(order, offset, limit)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return utils.RenderError(c, http.StatusInternalServerError, err)
 		}
 	}
-	return c.JSON(http.StatusOK, newItemListResponse(h.playerStore, playerIDFromToken(c), items, count))
+	return h.renderItemList(c, items, count)
 }
 
 func (h *Handler) Feed(c echo.Context) error {
 	var items []model.Item
 	var count int
+	order, err := listOrder(c)
+	if err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
 	offset, err := strconv.Atoi(c.QueryParam("offset"))
 	if err != nil {
 		offset = 0
@@ -71,89 +85,115 @@ func (h *Handler) Feed(c echo.Context) error {
 	if err != nil {
 		limit = 20
 	}
-	items, count, err = h.itemStore.ListFeed(playerIDFromToken(c), offset, limit)
+	items, count, err = h.items(c).ListFeed(playerIDFromToken(c), order, offset, limit)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, nil)
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newItemListResponse(h.playerStore, playerIDFromToken(c), items, count))
+	return h.renderItemList(c, items, count)
 }
 
 func (h *Handler) CreateItem(c echo.Context) error {
 	var a model.Item
 	req := &itemCreateRequest{}
 	if err := req.bind(c, &a); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
 	}
 	a.AuthorID = playerIDFromToken(c)
-	err := h.itemStore.CreateItem(&a)
+	err := h.items(c).CreateItem(&a)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 
-	return c.JSON(http.StatusCreated, newItemResponse(c, &a))
+	itemsCreated.Inc()
+	return h.renderItem(c, http.StatusCreated, &a)
 }
 
 func (h *Handler) UpdateItem(c echo.Context) error {
+	return h.updateItem(c, false)
+}
+
+// PatchItem updates only the members named by an RFC 7396 merge patch or
+// RFC 6902 JSON patch; tags are kept unless the patch touches tagList.
+func (h *Handler) PatchItem(c echo.Context) error {
+	return h.updateItem(c, true)
+}
+
+func (h *Handler) updateItem(c echo.Context, patch bool) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetPlayerItemBySlug(playerIDFromToken(c), slug)
+	a, err := h.items(c).GetPlayerItemBySlug(playerIDFromToken(c), slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	if _, etag, err := h.itemRepresentation(c, a); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if err := utils.CheckIfMatch(c, etag); err != nil {
+		return utils.RenderError(c, http.StatusPreconditionFailed, err)
 	}
 	req := &itemUpdateRequest{}
 	req.populate(a)
-	if err := req.bind(c, a); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	bind := req.bind
+	if patch {
+		bind = req.patch
+	}
+	if err := bind(c, a); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
 	}
-	if err = h.itemStore.UpdateItem(a, req.Items.Tags); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+	if err = h.items(c).UpdateItem(a, req.Items.Tags); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newItemResponse(c, a))
+	return h.renderItem(c, http.StatusOK, a)
 }
 
 func (h *Handler) DeleteItem(c echo.Context) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetPlayerItemBySlug(playerIDFromToken(c), slug)
+	a, err := h.items(c).GetPlayerItemBySlug(playerIDFromToken(c), slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	err = h.itemStore.DeleteItem(a)
+	if _, etag, err := h.itemRepresentation(c, a); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if err := utils.CheckIfMatch(c, etag); err != nil {
+		return utils.RenderError(c, http.StatusPreconditionFailed, err)
+	}
+	err = h.items(c).DeleteItem(a)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"result": "ok"})
+	return c.JSON(http.StatusOK, newResultResponse())
 }
 
 func (h *Handler) AddComment(c echo.Context) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetBySlug(slug)
+	a, err := h.items(c).GetBySlug(slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
 	var cm model.Comment
 	req := &createCommentRequest{}
 	if err := req.bind(c, &cm); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
 	}
-	if err = h.itemStore.AddComment(a, &cm); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+	if err = h.items(c).AddComment(a, &cm); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
+	commentsPosted.Inc()
 	return c.JSON(http.StatusCreated, newCommentResponse(c, &cm))
 }
 
 func (h *Handler) GetComments(c echo.Context) error {
 	slug := c.Param("slug")
-	cm, err := h.itemStore.GetCommentsBySlug(slug)
+	cm, err := h.items(c).GetCommentsBySlug(slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	return c.JSON(http.StatusOK, newCommentListResponse(c, cm))
 }
@@ -162,58 +202,87 @@ func (h *Handler) DeleteComment(c echo.Context) error {
 	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	id := uint(id64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.NewError(err))
+		return utils.RenderError(c, http.StatusBadRequest, err)
 	}
-	cm, err := h.itemStore.GetCommentByID(id)
+	cm, err := h.items(c).GetCommentByID(id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if cm == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
 	if cm.PlayerID != playerIDFromToken(c) {
-		return c.JSON(http.StatusUnauthorized, utils.NewError(errors.New("unauthorized action")))
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
 	}
-	if err := h.itemStore.DeleteComment(cm); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+	if err := h.items(c).DeleteComment(cm); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"result": "ok"})
+	return c.JSON(http.StatusOK, newResultResponse())
 }
 
 func (h *Handler) Favorite(c echo.Context) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetBySlug(slug)
+	a, err := h.items(c).GetBySlug(slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	if err := h.itemStore.AddFavorite(a, playerIDFromToken(c)); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	if err := h.items(c).AddFavorite(a, playerIDFromToken(c)); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newItemResponse(c, a))
+	return h.renderItem(c, http.StatusOK, a)
 }
 
 func (h *Handler) Unfavorite(c echo.Context) error {
 	slug := c.Param("slug")
-	a, err := h.itemStore.GetBySlug(slug)
+	a, err := h.items(c).GetBySlug(slug)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if a == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	if err := h.itemStore.RemoveFavorite(a, playerIDFromToken(c)); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	if err := h.items(c).RemoveFavorite(a, playerIDFromToken(c)); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newItemResponse(c, a))
+	return h.renderItem(c, http.StatusOK, a)
 }
 
 func (h *Handler) Tags(c echo.Context) error {
-	tags, err := h.itemStore.ListTags()
+	tags, err := h.items(c).ListTags()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err)
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	return c.JSON(http.StatusOK, newTagListResponse(tags))
 }
+
+// renderItem writes a with its ETag.
+func (h *Handler) renderItem(c echo.Context, status int, a *model.Item) error {
+	r, etag, err := h.itemRepresentation(c, a)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	c.Response().Header().Set("ETag", etag)
+	return c.JSON(status, r)
+}
+
+// itemRepresentation builds a's response for the viewer and its ETag.
+func (h *Handler) itemRepresentation(c echo.Context, a *model.Item) (*singleItemResponse, string, error) {
+	m, err := newItemMeta(h.items(c), h.players(c), playerIDFromToken(c), []model.Item{*a})
+	if err != nil {
+		return nil, "", err
+	}
+	r := newItemResponse(a, m)
+	etag, err := representationETag(c, r)
+	return r, etag, err
+}
+
+func (h *Handler) renderItemList(c echo.Context, items []model.Item, count int) error {
+	m, err := newItemMeta(h.items(c), h.players(c), playerIDFromToken(c), items)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newItemListResponse(items, count, m))
+}
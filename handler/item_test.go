@@ -2,15 +2,26 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jinzhu/gorm"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/cache"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+	"golang-starter-pack/render"
 	"golang-starter-pack/router"
 	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/store"
 	"golang-starter-pack/utils"
 )
 
@@ -31,6 +42,23 @@ func TestListItemsCaseSuccess(t *testing.T) {
 	}
 }
 
+func TestListItemsCaseUnknownFilter(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	for _, q := range []string{"tag=nosuchtag", "author=nobody", "favorited=nobody"} {
+		req := httptest.NewRequest(echo.GET, "/api/items?"+q, nil)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h.Items(e.NewContext(req, rec)))
+		if assert.Equal(t, http.StatusOK, rec.Code, q) {
+			var aa itemListResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &aa))
+			assert.Equal(t, 0, aa.ItemsCount, q)
+			assert.NotNil(t, aa.Items, q)
+		}
+	}
+}
+
 func TestGetItemsCaseSuccess(t *testing.T) {
 	tearDown()
 	setup()
@@ -225,6 +253,59 @@ func TestDeleteCommentCaseSuccess(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestDeleteCommentCaseForbidden(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.DELETE, "/api/items/:slug/comments/:id", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(2)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/items/:slug/comments/:id")
+	c.SetParamNames("slug", "id")
+	c.SetParamValues("item1-slug", "1")
+	err := jwtMiddleware(func(context echo.Context) error {
+		return h.DeleteComment(c)
+	})(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	cm, err := as.GetCommentByID(1)
+	assert.NoError(t, err)
+	assert.NotNil(t, cm)
+}
+
+// failingItemStore fails writes with the kind of error drivers report.
+type failingItemStore struct {
+	item.Store
+}
+
+func (failingItemStore) AddFavorite(*model.Item, uint) error {
+	return errors.New("UNIQUE constraint failed: favorites.item_id")
+}
+
+func TestFavoriteCaseStoreError(t *testing.T) {
+	tearDown()
+	setup()
+	h := NewHandler(us, failingItemStore{as})
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.POST, "/api/items/:slug/favorite", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(2)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/items/:slug/favorite")
+	c.SetParamNames("slug")
+	c.SetParamValues("item1-slug")
+	assert.NoError(t, jwtMiddleware(h.Favorite)(c))
+	if assert.Equal(t, http.StatusInternalServerError, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeInternal, res.Errors.Code)
+		assert.Equal(t, "internal server error", res.Errors.Message)
+		assert.NotContains(t, rec.Body.String(), "UNIQUE")
+	}
+}
+
 func TestFavoriteCaseSuccess(t *testing.T) {
 	tearDown()
 	setup()
@@ -294,3 +375,307 @@ func TestGetTagsCaseSuccess(t *testing.T) {
 		assert.Contains(t, tt.Tags, "tag2")
 	}
 }
+
+func TestCreateItemsCaseInvalidTags(t *testing.T) {
+	tearDown()
+	setup()
+	var (
+		reqJSON = `{"item":{"title":"item2", "description":"item2", "body":"item2", "tagList":["Bad Tag"]}}`
+	)
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.POST, "/api/items", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err := jwtMiddleware(func(context echo.Context) error {
+		return h.CreateItem(c)
+	})(c)
+	assert.NoError(t, err)
+	if assert.Equal(t, http.StatusUnprocessableEntity, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "tag", res.Errors.Fields["tagList[0]"].Code)
+	}
+}
+
+func TestPatchItemCaseSuccess(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	patch := func(contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.PATCH, "/api/items/:slug", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/items/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("item1-slug")
+		assert.NoError(t, jwtMiddleware(h.PatchItem)(c))
+		return rec
+	}
+
+	rec := patch(utils.MIMEMergePatch, `{"item":{"body":"patched body","description":null}}`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var a singleItemResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &a))
+		assert.Equal(t, "item1 title", a.Item.Title)
+		assert.Equal(t, "patched body", a.Item.Body)
+		assert.ElementsMatch(t, []string{"tag1", "tag2"}, a.Item.TagList)
+	}
+
+	rec = patch(utils.MIMEJSONPatch, `[{"op":"test","path":"/item/title","value":"item1 title"},{"op":"add","path":"/item/tagList/-","value":"tag3"},{"op":"remove","path":"/item/tagList/0"}]`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var a singleItemResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &a))
+		assert.ElementsMatch(t, []string{"tag2", "tag3"}, a.Item.TagList)
+		assert.Equal(t, "patched body", a.Item.Body)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(utils.MIMEJSONPatch, `[{"op":"test","path":"/item/title","value":"other"}]`).Code)
+	// A null value is a value; only a missing one is rejected.
+	rec = patch(utils.MIMEJSONPatch, `[{"op":"add","path":"/item/body","value":null},{"op":"test","path":"/item/body","value":null},{"op":"replace","path":"/item/body","value":"body again"}]`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var a singleItemResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &a))
+		assert.Equal(t, "body again", a.Item.Body)
+	}
+	assert.Equal(t, http.StatusUnprocessableEntity, patch(utils.MIMEJSONPatch, `[{"op":"replace","path":"/item/title","value":null}]`).Code)
+	assert.Equal(t, http.StatusBadRequest, patch(utils.MIMEJSONPatch, `[{"op":"replace","path":"/item/title"}]`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, patch(utils.MIMEMergePatch, `{"item":{"title":null}}`).Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, patch(echo.MIMEApplicationJSON, `{"item":{}}`).Code)
+}
+
+func TestItemETagCase(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	viewer := uint(1)
+	do := func(method string, hf echo.HandlerFunc, header, value, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/items/:slug", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(viewer)))
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/items/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("item1-slug")
+		assert.NoError(t, jwtMiddleware(hf)(c))
+		return rec
+	}
+
+	rec := do(echo.GET, h.GetItem, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	rec = do(echo.GET, h.GetItem, "If-None-Match", etag, "")
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = do(echo.PUT, h.UpdateItem, "If-Match", `"stale"`, `{"item":{"body":"lost update"}}`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	rec = do(echo.PUT, h.UpdateItem, "If-Match", etag, `{"item":{"body":"new body"}}`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	}
+
+	assert.Equal(t, http.StatusOK, do(echo.GET, h.GetItem, "If-None-Match", etag, "").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, do(echo.DELETE, h.DeleteItem, "If-Match", etag, "").Code)
+
+	// Counters, the viewer's flags and the format are part of the tag.
+	etag = do(echo.GET, h.GetItem, "", "", "").Header().Get("ETag")
+	a, _ := as.GetBySlug("item1-slug")
+	assert.NoError(t, as.AddFavorite(a, 2))
+	assert.Equal(t, http.StatusOK, do(echo.GET, h.GetItem, "If-None-Match", etag, "").Code)
+	etag = do(echo.GET, h.GetItem, "", "", "").Header().Get("ETag")
+	assert.NotEqual(t, etag, do(echo.GET, h.GetItem, echo.HeaderAccept, render.MIMEMsgPack, "").Header().Get("ETag"))
+	viewer = 2
+	assert.Equal(t, http.StatusOK, do(echo.GET, h.GetItem, "If-None-Match", etag, "").Code)
+
+	e := router.New()
+	e.Pre(middleware.StripAPIVersion("/api"))
+	h.Register(e.Group("/api"))
+	get := func(path string) string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, path, nil))
+		return rec.Header().Get("ETag")
+	}
+	assert.NotEqual(t, get("/api/v1/items/item1-slug"), get("/api/v2/items/item1-slug"))
+}
+
+func TestCachedItemStoreCaseInvalidation(t *testing.T) {
+	tearDown()
+	setup()
+	cs := store.NewCachedItemStore(as, cache.NewLRU(100), time.Minute)
+	ch := NewHandler(us, cs)
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	do := func(method string, hf echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/items/:slug", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/items/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("item1-slug")
+		assert.NoError(t, jwtMiddleware(hf)(c))
+		return rec
+	}
+	get := func() singleItemResponse {
+		var a singleItemResponse
+		rec := do(echo.GET, ch.GetItem, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &a))
+		return a
+	}
+
+	get()
+	a := get()
+	assert.Equal(t, "item1 body", a.Item.Body)
+	assert.Equal(t, uint64(1), cs.Stats().Hits())
+	assert.Equal(t, uint64(1), cs.Stats().Misses())
+
+	assert.Equal(t, http.StatusOK, do(echo.PUT, ch.UpdateItem, `{"item":{"body":"cached body"}}`).Code)
+	assert.Equal(t, "cached body", get().Item.Body)
+
+	assert.Equal(t, http.StatusOK, do(echo.POST, ch.Favorite, "").Code)
+	a = get()
+	assert.True(t, a.Item.Favorited)
+	assert.Equal(t, 1, a.Item.FavoritesCount)
+	assert.Equal(t, 0.4, cs.Stats().HitRate())
+}
+
+// queryCounter is a gorm logger counting the SQL statements it is shown.
+type queryCounter int
+
+func (q *queryCounter) Print(v ...interface{}) {
+	if len(v) > 0 && v[0] == "sql" {
+		*q++
+	}
+}
+
+// countQueries returns the number of SQL statements fn runs.
+func countQueries(fn func()) int {
+	var q queryCounter
+	d.SetLogger(&q)
+	d.LogMode(true)
+	defer func() {
+		d.LogMode(false)
+		d.SetLogger(gorm.Logger{LogWriter: log.New(os.Stdout, "\r\n", 0)})
+	}()
+	fn()
+	return int(q)
+}
+
+// loadListFixtures adds n items by player2, each favorited by player1.
+func loadListFixtures(n int) {
+	for i := 0; i < n; i++ {
+		a := model.Item{
+			Slug:        fmt.Sprintf("list-item-%d", i),
+			Title:       fmt.Sprintf("list item %d", i),
+			Description: "list item description",
+			Body:        "list item body",
+			AuthorID:    2,
+			Tags:        []model.Tag{{Tag: "tag1"}},
+		}
+		as.CreateItem(&a)
+		as.AddFavorite(&a, 1)
+	}
+}
+
+func listItems(t testing.TB, limit int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(echo.GET, fmt.Sprintf("/api/items?limit=%d", limit), nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, middleware.JWT(utils.JWTSecret)(h.Items)(c))
+	return rec
+}
+
+func TestListItemsCaseConstantQueries(t *testing.T) {
+	tearDown()
+	setup()
+	loadListFixtures(30)
+	small := countQueries(func() { listItems(t, 2) })
+	large := countQueries(func() { listItems(t, 30) })
+	assert.Equal(t, small, large)
+
+	rec := listItems(t, 30)
+	var ar itemListResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ar))
+	if assert.Len(t, ar.Items, 30) {
+		for _, a := range ar.Items {
+			assert.True(t, a.Favorited)
+			assert.Equal(t, 1, a.FavoritesCount)
+			assert.True(t, a.Author.Following)
+		}
+	}
+}
+
+func BenchmarkListItemsQueries(b *testing.B) {
+	tearDown()
+	setup()
+	loadListFixtures(100)
+	for _, limit := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			n := 0
+			for i := 0; i < b.N; i++ {
+				n += countQueries(func() { listItems(b, limit) })
+			}
+			b.ReportMetric(float64(n)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestItemCountersCase(t *testing.T) {
+	tearDown()
+	setup()
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.GET, "/api/items?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, h.Items(c))
+		return rec
+	}
+	slugs := func(query string) []string {
+		var ar itemListResponse
+		assert.NoError(t, json.Unmarshal(list(query).Body.Bytes(), &ar))
+		s := make([]string, 0)
+		for _, a := range ar.Items {
+			s = append(s, a.Slug)
+		}
+		return s
+	}
+
+	a, _ := as.GetBySlug("item1-slug")
+	assert.Equal(t, 0, a.FavoritesCount)
+	assert.Equal(t, 1, a.CommentsCount)
+	assert.NoError(t, as.AddComment(a, &model.Comment{Body: "another", PlayerID: 2}))
+	assert.Equal(t, 2, a.CommentsCount)
+	assert.NoError(t, as.AddFavorite(a, 2))
+	assert.NoError(t, as.AddFavorite(a, 2))
+	assert.Equal(t, 1, a.FavoritesCount)
+	p2, _ := us.GetByUsername("player2")
+	assert.Equal(t, 1, p2.FollowersCount)
+
+	assert.Equal(t, []string{"item2-slug", "item1-slug"}, slugs(""))
+	assert.Equal(t, []string{"item1-slug", "item2-slug"}, slugs("sort=comments"))
+	assert.Equal(t, http.StatusUnprocessableEntity, list("sort=random").Code)
+
+	d.Exec("UPDATE items SET favorites_count = 7, comments_count = 0")
+	n, err := as.(*store.ItemStore).ReconcileCounters()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	a, _ = as.GetBySlug("item1-slug")
+	assert.Equal(t, 1, a.FavoritesCount)
+	assert.Equal(t, 2, a.CommentsCount)
+	n, _ = us.(*store.PlayerStore).ReconcileCounters()
+	assert.Equal(t, int64(0), n)
+}
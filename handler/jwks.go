@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/utils"
+)
+
+// JWKS publishes the public keys tokens are verified with. It is mounted
+// at /.well-known/jwks.json, outside the API group.
+func (h *Handler) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, utils.DefaultKeySet.JWKS())
+}
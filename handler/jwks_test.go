@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+func TestJWTKeyRotation(t *testing.T) {
+	tearDown()
+	setup()
+	defer utils.DefaultKeySet.SetActive(utils.DefaultKeyID)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	utils.DefaultKeySet.Add(&utils.SigningKey{ID: "rsa-1", Method: jwt.SigningMethodRS256, Private: rsaKey, Public: rsaKey.Public()})
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	utils.DefaultKeySet.Add(&utils.SigningKey{ID: "ed-1", Method: utils.SigningMethodEdDSA, Private: edPriv, Public: edPub})
+	defer utils.DefaultKeySet.Remove("rsa-1")
+	defer utils.DefaultKeySet.Remove("ed-1")
+
+	jwtMiddleware := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeySet:   utils.DefaultKeySet,
+		Issuer:   utils.JWTIssuer,
+		Audience: utils.JWTAudience,
+	})
+	currentPlayer := func(token string) int {
+		req := httptest.NewRequest(echo.GET, "/api/player", nil)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(token))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, jwtMiddleware(h.CurrentPlayer)(c))
+		return rec.Code
+	}
+
+	assert.NoError(t, utils.DefaultKeySet.SetActive("rsa-1"))
+	rsaToken := utils.GenerateJWT(1)
+	assert.Equal(t, http.StatusOK, currentPlayer(rsaToken))
+
+	assert.NoError(t, utils.DefaultKeySet.SetActive("ed-1"))
+	assert.Equal(t, http.StatusOK, currentPlayer(utils.GenerateJWT(1)))
+	assert.Equal(t, http.StatusOK, currentPlayer(rsaToken))
+
+	req := httptest.NewRequest(echo.GET, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h.JWKS(e.NewContext(req, rec)))
+	var set utils.JWKS
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	kids := make([]string, 0)
+	for _, k := range set.Keys {
+		kids = append(kids, k.Kid)
+	}
+	assert.ElementsMatch(t, []string{"rsa-1", "ed-1"}, kids)
+
+	assert.NoError(t, utils.DefaultKeySet.Remove("rsa-1"))
+	assert.Equal(t, http.StatusForbidden, currentPlayer(rsaToken))
+}
+
+func TestJWTWrongAudience(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeySet:   utils.DefaultKeySet,
+		Audience: "someone-else",
+	})
+	req := httptest.NewRequest(echo.GET, "/api/player", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.CurrentPlayer)(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestLoadKeyDirDropsDefaultKey(t *testing.T) {
+	tearDown()
+	setup()
+	dir, err := ioutil.TempDir("", "keys")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "rsa-1.pem"), pemData, 0600))
+
+	keys := utils.NewDefaultKeySet()
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "1", "iss": utils.JWTIssuer, "aud": utils.JWTAudience, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	forged.Header["kid"] = utils.DefaultKeyID
+	token, err := forged.SignedString(utils.JWTSecret)
+	assert.NoError(t, err)
+
+	jwtMiddleware := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeySet:   keys,
+		Issuer:   utils.JWTIssuer,
+		Audience: utils.JWTAudience,
+	})
+	currentPlayer := func(token string) int {
+		req := httptest.NewRequest(echo.GET, "/api/player", nil)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(token))
+		rec := httptest.NewRecorder()
+		assert.NoError(t, jwtMiddleware(h.CurrentPlayer)(e.NewContext(req, rec)))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, currentPlayer(token))
+
+	assert.Error(t, keys.LoadKeyDir(dir, utils.DefaultKeyID))
+	assert.NoError(t, keys.LoadKeyDir(dir, "rsa-1"))
+	assert.Equal(t, http.StatusForbidden, currentPlayer(token))
+	signed, err := keys.Sign(jwt.MapClaims{"sub": "1", "iss": utils.JWTIssuer, "aud": utils.JWTAudience, "exp": time.Now().Add(time.Hour).Unix()})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, currentPlayer(signed))
+}
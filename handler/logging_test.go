@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/db"
+	"golang-starter-pack/logging"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+)
+
+func TestLoggingCaseRequestFields(t *testing.T) {
+	tearDown()
+	setup()
+	var buf bytes.Buffer
+	defer func(l *logging.Logger) { logging.Default = l }(logging.Default)
+	logging.Default = logging.New(&buf, logging.FormatJSON, logging.LevelDebug)
+	db.Log(d, logging.Default)
+	h.SetLogger(logging.Default)
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(`{"player":{"username":"logger","email":"logger@realworld.io","password":"hunter22"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(router.RequestIDHeader, "signup-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "signup-1", rec.Header().Get(router.RequestIDHeader))
+
+	req = httptest.NewRequest(echo.GET, "/api/player", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	generated := rec.Header().Get(router.RequestIDHeader)
+	assert.Len(t, generated, 32)
+
+	out := buf.String()
+	assert.NotContains(t, out, "logger@realworld.io")
+	assert.NotContains(t, out, "hunter22")
+
+	var sawSQL, sawSignup, sawPlayer bool
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var m map[string]interface{}
+		if !assert.NoError(t, json.Unmarshal([]byte(line), &m), line) {
+			continue
+		}
+		assert.Contains(t, []interface{}{"signup-1", generated}, m["request_id"], line)
+		switch {
+		case m["msg"] == "sql" && m["request_id"] == "signup-1":
+			sawSQL = true
+			assert.NotContains(t, m["sql"], "hunter22")
+		case m["msg"] == "player signed up":
+			sawSignup = true
+		case m["msg"] == "request" && m["request_id"] == generated:
+			sawPlayer = true
+			assert.Equal(t, float64(1), m["player_id"])
+			assert.Equal(t, "/api/player", m["route"])
+			assert.Equal(t, float64(200), m["status"])
+		}
+	}
+	assert.True(t, sawSQL)
+	assert.True(t, sawSignup)
+	assert.True(t, sawPlayer)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"golang-starter-pack/metrics"
+)
+
+var (
+	signups = metrics.Factory.NewCounter(prometheus.CounterOpts{
+		Name: "signups_total", Help: "Players signed up."})
+	itemsCreated = metrics.Factory.NewCounter(prometheus.CounterOpts{
+		Name: "items_created_total", Help: "Items created."})
+	commentsPosted = metrics.Factory.NewCounter(prometheus.CounterOpts{
+		Name: "comments_posted_total", Help: "Comments posted on items."})
+)
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/db"
+	"golang-starter-pack/metrics"
+	"golang-starter-pack/router"
+)
+
+func TestMetricsCaseSuccess(t *testing.T) {
+	tearDown()
+	setup()
+	db.Instrument(d)
+	e := router.New()
+	h.Register(e.Group("/api"))
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	req := httptest.NewRequest(echo.GET, "/api/tags", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(`{"player":{"username":"metrics","email":"metrics@realworld.io","password":"secret123"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(echo.GET, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/api/tags",status="200"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/api/tags",le="+Inf"} 1`)
+	assert.Contains(t, body, "# TYPE signups_total counter\nsignups_total ")
+	assert.NotContains(t, body, "signups_total 0\n")
+	assert.Contains(t, body, `db_query_duration_seconds_count{operation="create"}`)
+	assert.Contains(t, body, "db_open_connections ")
+	assert.Contains(t, body, "go_goroutines ")
+	assert.Contains(t, body, "process_cpu_seconds_total ")
+}
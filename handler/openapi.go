@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+	"golang-starter-pack/openapi"
+	"golang-starter-pack/render"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+)
+
+// Who may call an operation, mirroring the middleware in Register.
+type authKind int
+
+const (
+	authNone authKind = iota
+	authOptional
+	authRequired
+	// authSession excludes API keys, like middleware.RequireSession.
+	authSession
+)
+
+// oneOf documents a response that takes one of several shapes.
+type oneOf []interface{}
+
+// apiOperation documents one route of Register. Paths are relative to the
+// API group and written the way Echo routes them.
+type apiOperation struct {
+	method, path string
+	id, summary  string
+	tag          string
+	auth         authKind
+	scope        string
+	query        []*openapi.Parameter
+	request      interface{}
+	patch        bool
+	status       int
+	response     interface{}
+	html         bool
+	text         bool
+	// csv marks list responses that can also be rendered as CSV.
+	csv bool
+	// feed marks RSS or Atom feeds, told apart by the path's extension.
+	feed bool
+}
+
+var (
+	pageParams = []*openapi.Parameter{
+		{Name: "limit", In: "query", Description: "Items per page, 20 by default.", Schema: &openapi.Schema{Type: "integer", Format: "int32"}},
+		{Name: "offset", In: "query", Description: "Items to skip.", Schema: &openapi.Schema{Type: "integer", Format: "int32"}},
+		{Name: "sort", In: "query", Description: "Order of the items.", Schema: &openapi.Schema{
+			Type: "string", Enum: []interface{}{string(item.OrderRecent), string(item.OrderFavorites), string(item.OrderComments)}}},
+	}
+	listParams = append([]*openapi.Parameter{
+		{Name: "tag", In: "query", Description: "Only items with this tag.", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "author", In: "query", Description: "Only items by this player.", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "favorited", In: "query", Description: "Only items this player favorited.", Schema: &openapi.Schema{Type: "string"}},
+	}, pageParams...)
+	graphQLQueryParams = []*openapi.Parameter{
+		{Name: "query", In: "query", Required: true, Description: "The query document; mutations must be POSTed.", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "operationName", In: "query", Description: "The operation to run when the document has several.", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "variables", In: "query", Description: "Variables as a JSON object.", Schema: &openapi.Schema{Type: "string"}},
+	}
+)
+
+// apiOperations must list every route of Register; the tests fail when
+// they drift apart.
+var apiOperations = []apiOperation{
+	{method: echo.POST, path: "/players", id: "signUp", summary: "Register a player", tag: "players",
+		request: playerRegisterRequest{}, status: http.StatusCreated, response: playerResponse{}},
+	{method: echo.POST, path: "/players/login", id: "login", summary: "Log in, or start a two-factor challenge", tag: "players",
+		request: playerLoginRequest{}, status: http.StatusOK, response: oneOf{playerResponse{}, loginChallengeResponse{}}},
+	{method: echo.POST, path: "/players/login/2fa", id: "loginTwoFactor", summary: "Complete a two-factor challenge", tag: "players",
+		request: twoFactorLoginRequest{}, status: http.StatusOK, response: playerResponse{}},
+
+	{method: echo.GET, path: "/player", id: "getCurrentPlayer", summary: "Get the logged in player", tag: "player",
+		auth: authRequired, status: http.StatusOK, response: playerResponse{}},
+	{method: echo.POST, path: "/player/logout", id: "logout", summary: "Clear the session cookies", tag: "player",
+		auth: authRequired, status: http.StatusOK, response: resultResponse{}},
+	{method: echo.PUT, path: "/player", id: "updatePlayer", summary: "Update the logged in player", tag: "player",
+		auth: authRequired, scope: model.ScopeProfileWrite, request: playerUpdateRequest{}, status: http.StatusOK, response: playerResponse{}},
+	{method: echo.PATCH, path: "/player", id: "patchPlayer", summary: "Patch the logged in player", tag: "player",
+		auth: authRequired, scope: model.ScopeProfileWrite, request: playerUpdateRequest{}, patch: true, status: http.StatusOK, response: playerResponse{}},
+	{method: echo.POST, path: "/player/2fa", id: "enrollTwoFactor", summary: "Start two-factor enrollment", tag: "player",
+		auth: authSession, status: http.StatusOK, response: twoFactorSetupResponse{}},
+	{method: echo.POST, path: "/player/2fa/verify", id: "verifyTwoFactor", summary: "Confirm two-factor enrollment", tag: "player",
+		auth: authSession, request: twoFactorVerifyRequest{}, status: http.StatusOK, response: recoveryCodesResponse{}},
+	{method: echo.DELETE, path: "/player/2fa", id: "disableTwoFactor", summary: "Turn two-factor authentication off", tag: "player",
+		auth: authSession, request: twoFactorDisableRequest{}, status: http.StatusOK, response: resultResponse{}},
+	{method: echo.POST, path: "/player/keys", id: "createAPIKey", summary: "Create an API key", tag: "player",
+		auth: authSession, request: apiKeyCreateRequest{}, status: http.StatusCreated, response: singleAPIKeyResponse{}},
+	{method: echo.GET, path: "/player/keys", id: "listAPIKeys", summary: "List API keys", tag: "player",
+		auth: authSession, status: http.StatusOK, response: apiKeyListResponse{}},
+	{method: echo.DELETE, path: "/player/keys/:id", id: "revokeAPIKey", summary: "Revoke an API key", tag: "player",
+		auth: authSession, status: http.StatusOK, response: resultResponse{}},
+
+	{method: echo.GET, path: "/profiles/:username", id: "getProfile", summary: "Get a profile", tag: "profiles",
+		auth: authRequired, status: http.StatusOK, response: profileResponse{}},
+	{method: echo.POST, path: "/profiles/:username/follow", id: "followPlayer", summary: "Follow a player", tag: "profiles",
+		auth: authRequired, scope: model.ScopeProfileWrite, status: http.StatusOK, response: profileResponse{}},
+	{method: echo.DELETE, path: "/profiles/:username/follow", id: "unfollowPlayer", summary: "Unfollow a player", tag: "profiles",
+		auth: authRequired, scope: model.ScopeProfileWrite, status: http.StatusOK, response: profileResponse{}},
+
+	{method: echo.POST, path: "/items", id: "createItem", summary: "Create an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, request: itemCreateRequest{}, status: http.StatusCreated, response: singleItemResponse{}},
+	{method: echo.GET, path: "/items/feed", id: "getFeed", summary: "List items by followed players", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsRead, query: pageParams, status: http.StatusOK, response: itemListResponse{}, csv: true},
+	{method: echo.PUT, path: "/items/:slug", id: "updateItem", summary: "Update an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, request: itemUpdateRequest{}, status: http.StatusOK, response: singleItemResponse{}},
+	{method: echo.PATCH, path: "/items/:slug", id: "patchItem", summary: "Patch an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, request: itemUpdateRequest{}, patch: true, status: http.StatusOK, response: singleItemResponse{}},
+	{method: echo.DELETE, path: "/items/:slug", id: "deleteItem", summary: "Delete an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, status: http.StatusOK, response: resultResponse{}},
+	{method: echo.POST, path: "/items/:slug/comments", id: "addComment", summary: "Comment on an item", tag: "items",
+		auth: authRequired, scope: model.ScopeCommentsWrite, request: createCommentRequest{}, status: http.StatusCreated, response: singleCommentResponse{}},
+	{method: echo.DELETE, path: "/items/:slug/comments/:id", id: "deleteComment", summary: "Delete a comment", tag: "items",
+		auth: authRequired, scope: model.ScopeCommentsWrite, status: http.StatusOK, response: resultResponse{}},
+	{method: echo.POST, path: "/items/:slug/favorite", id: "favoriteItem", summary: "Favorite an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, status: http.StatusOK, response: singleItemResponse{}},
+	{method: echo.DELETE, path: "/items/:slug/favorite", id: "unfavoriteItem", summary: "Unfavorite an item", tag: "items",
+		auth: authRequired, scope: model.ScopeItemsWrite, status: http.StatusOK, response: singleItemResponse{}},
+	{method: echo.GET, path: "/items", id: "listItems", summary: "List items", tag: "items",
+		auth: authOptional, scope: model.ScopeItemsRead, query: listParams, status: http.StatusOK, response: itemListResponse{}, csv: true},
+	{method: echo.GET, path: "/items/:slug", id: "getItem", summary: "Get an item", tag: "items",
+		auth: authOptional, scope: model.ScopeItemsRead, status: http.StatusOK, response: singleItemResponse{}},
+	{method: echo.GET, path: "/items/:slug/comments", id: "listComments", summary: "List the comments on an item", tag: "items",
+		auth: authOptional, scope: model.ScopeItemsRead, status: http.StatusOK, response: commentListResponse{}},
+
+	{method: echo.GET, path: "/tags", id: "listTags", summary: "List tags", tag: "items",
+		status: http.StatusOK, response: tagListResponse{}, csv: true},
+
+	{method: echo.GET, path: "/items.rss", id: "getItemsRSS", summary: "Latest items as RSS", tag: "feeds",
+		status: http.StatusOK, feed: true},
+	{method: echo.GET, path: "/items.atom", id: "getItemsAtom", summary: "Latest items as Atom", tag: "feeds",
+		status: http.StatusOK, feed: true},
+	{method: echo.GET, path: "/profiles/:username/items.rss", id: "getAuthorItemsRSS", summary: "Latest items by a player as RSS", tag: "feeds",
+		status: http.StatusOK, feed: true},
+	{method: echo.GET, path: "/profiles/:username/items.atom", id: "getAuthorItemsAtom", summary: "Latest items by a player as Atom", tag: "feeds",
+		status: http.StatusOK, feed: true},
+	{method: echo.GET, path: "/tags/:tag/items.rss", id: "getTagItemsRSS", summary: "Latest items with a tag as RSS", tag: "feeds",
+		status: http.StatusOK, feed: true},
+	{method: echo.GET, path: "/tags/:tag/items.atom", id: "getTagItemsAtom", summary: "Latest items with a tag as Atom", tag: "feeds",
+		status: http.StatusOK, feed: true},
+
+	{method: echo.POST, path: "/admin/players/:username/unlock", id: "unlockPlayer", summary: "Lift a login lockout", tag: "admin",
+		auth: authSession, status: http.StatusOK, response: resultResponse{}},
+	{method: echo.GET, path: "/admin/cache", id: "getCacheStats", summary: "Get item cache statistics", tag: "admin",
+		auth: authSession, status: http.StatusOK, response: cacheStatsResponse{}},
+
+	{method: echo.GET, path: "/graphql", id: "graphQLQuery", summary: "Run a GraphQL query", tag: "graphql",
+		auth: authOptional, query: graphQLQueryParams, status: http.StatusOK, response: map[string]interface{}{}},
+	{method: echo.POST, path: "/graphql", id: "graphQL", summary: "Run a GraphQL query or mutation", tag: "graphql",
+		auth: authOptional, request: graphQLParams{}, status: http.StatusOK, response: map[string]interface{}{}},
+	{method: echo.GET, path: "/graphql/schema", id: "getGraphQLSchema", summary: "Get the GraphQL schema", tag: "graphql",
+		status: http.StatusOK, text: true},
+
+	{method: echo.GET, path: "/openapi.json", id: "getOpenAPI", summary: "Get this document", tag: "docs",
+		status: http.StatusOK, response: map[string]interface{}{}},
+	{method: echo.GET, path: "/docs", id: "getDocs", summary: "Browse this document in Swagger UI", tag: "docs",
+		status: http.StatusOK, html: true},
+}
+
+var apiTags = []openapi.Tag{
+	{Name: "players", Description: "Sign up and log in."},
+	{Name: "player", Description: "The logged in player's account."},
+	{Name: "profiles", Description: "Public player profiles."},
+	{Name: "items", Description: "Items, comments, favorites and tags."},
+	{Name: "feeds", Description: "RSS and Atom feeds of the latest items."},
+	{Name: "admin", Description: "Operations for admins."},
+	{Name: "graphql", Description: "The same data through GraphQL."},
+	{Name: "docs", Description: "API documentation."},
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+// OpenAPI builds the OpenAPI document of the routes in Register.
+func OpenAPI() *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Golang Starter Pack API",
+			Description: "Players publish items, comment on them, favorite them and follow each other.",
+			Version:     "1.0.0",
+		},
+		Servers: []openapi.Server{{URL: "/api"}},
+		Tags:    apiTags,
+		Paths:   make(map[string]*openapi.PathItem),
+		Components: openapi.Components{SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"token":   {Type: "apiKey", In: "header", Name: echo.HeaderAuthorization, Description: "A session token sent as `Token <jwt>`."},
+			"bearer":  {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			"apiKey":  {Type: "apiKey", In: "header", Name: echo.HeaderAuthorization, Description: "An API key sent as `ApiKey <key>`."},
+			"session": {Type: "apiKey", In: "cookie", Name: "session", Description: "The session cookie set on login."},
+		}},
+	}
+	g := openapi.NewGenerator(doc, router.DescribeValidation)
+	errorSchema := g.Response(utils.Error{})
+	for _, op := range apiOperations {
+		path := pathParam.ReplaceAllString(op.path, "{$1}")
+		pi, ok := doc.Paths[path]
+		if !ok {
+			pi = &openapi.PathItem{}
+			doc.Paths[path] = pi
+		}
+		(*pi)[strings.ToLower(op.method)] = describeOperation(g, op, errorSchema)
+	}
+	return doc
+}
+
+func describeOperation(g *openapi.Generator, op apiOperation, errorSchema *openapi.Schema) *openapi.Operation {
+	o := &openapi.Operation{
+		OperationID: op.id,
+		Summary:     op.summary,
+		Tags:        []string{op.tag},
+		Responses:   make(map[string]*openapi.Response),
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(op.path, -1) {
+		s := &openapi.Schema{Type: "string"}
+		if m[1] == "id" {
+			s = &openapi.Schema{Type: "integer", Format: "int32"}
+		}
+		o.Parameters = append(o.Parameters, &openapi.Parameter{Name: m[1], In: "path", Required: true, Schema: s})
+	}
+	o.Parameters = append(o.Parameters, op.query...)
+
+	errors := []int{http.StatusTooManyRequests, http.StatusInternalServerError}
+	if op.request != nil {
+		body := g.Request(op.request)
+		content := map[string]*openapi.MediaType{echo.MIMEApplicationJSON: {Schema: body}}
+		if op.patch {
+			content = map[string]*openapi.MediaType{
+				utils.MIMEMergePatch: {Schema: &openapi.Schema{Type: "object"}},
+				utils.MIMEJSONPatch:  {Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "object"}}},
+			}
+			o.Description = "Accepts a JSON Merge Patch or a JSON Patch of the shape sent to PUT."
+			errors = append(errors, http.StatusBadRequest, http.StatusUnsupportedMediaType)
+		}
+		o.RequestBody = &openapi.RequestBody{Required: true, Content: content}
+		errors = append(errors, http.StatusUnprocessableEntity)
+	}
+	switch op.auth {
+	case authRequired, authSession:
+		errors = append(errors, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	if strings.Contains(op.path, ":") {
+		errors = append(errors, http.StatusNotFound)
+	}
+	o.Security = security(op.auth)
+	if op.scope != "" && op.auth != authNone {
+		o.Description = strings.TrimSpace(o.Description + " API keys need the " + op.scope + " scope.")
+	}
+
+	ok := &openapi.Response{Description: http.StatusText(op.status)}
+	switch r := op.response.(type) {
+	case nil:
+		if op.html {
+			ok.Content = map[string]*openapi.MediaType{echo.MIMETextHTMLCharsetUTF8: {Schema: &openapi.Schema{Type: "string"}}}
+		}
+		if op.text {
+			ok.Content = map[string]*openapi.MediaType{echo.MIMETextPlainCharsetUTF8: {Schema: &openapi.Schema{Type: "string"}}}
+		}
+		if op.feed {
+			mime := mimeRSS
+			if strings.HasSuffix(op.path, ".atom") {
+				mime = mimeAtom
+			}
+			ok.Content = map[string]*openapi.MediaType{mime: {Schema: &openapi.Schema{Type: "string"}}}
+		}
+	case oneOf:
+		s := &openapi.Schema{}
+		for _, v := range r {
+			s.OneOf = append(s.OneOf, g.Response(v))
+		}
+		ok.Content = responseContent(s, op.csv)
+	default:
+		ok.Content = responseContent(g.Response(r), op.csv)
+	}
+	o.Responses[strconv.Itoa(op.status)] = ok
+	sort.Ints(errors)
+	for _, status := range errors {
+		o.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Description: http.StatusText(status),
+			Content:     responseContent(errorSchema, false),
+		}
+	}
+	return o
+}
+
+// responseContent lists the formats middleware.Render can answer in.
+func responseContent(s *openapi.Schema, csv bool) map[string]*openapi.MediaType {
+	content := map[string]*openapi.MediaType{
+		echo.MIMEApplicationJSON: {Schema: s},
+		render.MIMEMsgPack:       {Schema: s},
+		render.MIMECBOR:          {Schema: s},
+	}
+	if csv {
+		content[render.MIMECSV] = &openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+	}
+	return content
+}
+
+func security(auth authKind) []openapi.SecurityRequirement {
+	if auth == authNone {
+		return nil
+	}
+	reqs := []openapi.SecurityRequirement{{"token": {}}, {"bearer": {}}, {"session": {}}}
+	if auth != authSession {
+		reqs = append(reqs, openapi.SecurityRequirement{"apiKey": {}})
+	}
+	if auth == authOptional {
+		reqs = append(reqs, openapi.SecurityRequirement{})
+	}
+	return reqs
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIJSON []byte
+)
+
+// OpenAPISpec serves the OpenAPI document, built once.
+func (h *Handler) OpenAPISpec(c echo.Context) error {
+	openAPIOnce.Do(func() {
+		openAPIJSON, _ = json.MarshalIndent(OpenAPI(), "", "  ")
+	})
+	return c.JSONBlob(http.StatusOK, openAPIJSON)
+}
+
+// swaggerUI loads Swagger UI from a CDN and points it at the document.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Golang Starter Pack API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// SwaggerUI serves a page for browsing and trying the API.
+func (h *Handler) SwaggerUI(c echo.Context) error {
+	return c.HTML(http.StatusOK, swaggerUI)
+}
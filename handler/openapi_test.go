@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/openapi"
+	"golang-starter-pack/router"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+// routeParam rewrites Echo's :name path parameters the OpenAPI way.
+var routeParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+func TestOpenAPICaseNoDrift(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+	// Groups with middleware add catch-all routes that 404.
+	notFound := runtime.FuncForPC(reflect.ValueOf(echo.NotFoundHandler).Pointer()).Name()
+	var routes []string
+	for _, r := range e.Routes() {
+		if r.Name == notFound {
+			continue
+		}
+		path := routeParam.ReplaceAllString(strings.TrimPrefix(r.Path, "/api"), "{$1}")
+		routes = append(routes, r.Method+" "+path)
+	}
+	var documented []string
+	for path, item := range OpenAPI().Paths {
+		for method := range *item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	sort.Strings(documented)
+	assert.Equal(t, routes, documented, "routes in Register and apiOperations differ")
+}
+
+func TestOpenAPICaseSchemas(t *testing.T) {
+	doc := OpenAPI()
+	signUp := doc.Operation(echo.POST, "/players")
+	if assert.NotNil(t, signUp) {
+		body := doc.Resolve(signUp.RequestBody.Content[echo.MIMEApplicationJSON].Schema)
+		player := body.Properties["player"]
+		assert.ElementsMatch(t, []string{"username", "email", "password"}, player.Required)
+		assert.Equal(t, "email", player.Properties["email"].Format)
+		assert.Equal(t, 32, *player.Properties["username"].MaxLength)
+		assert.Equal(t, false, player.AdditionalProperties)
+		assert.Contains(t, signUp.Responses, "201")
+		assert.Contains(t, signUp.Responses, "422")
+	}
+	getItem := doc.Operation(echo.GET, "/items/{slug}")
+	if assert.NotNil(t, getItem) {
+		item := doc.Resolve(doc.Resolve(getItem.Responses["200"].Content[echo.MIMEApplicationJSON].Schema).Properties["item"])
+		assert.Equal(t, "date-time", item.Properties["createdAt"].Format)
+		assert.Contains(t, item.Required, "favoritesCount")
+		assert.Equal(t, "slug", getItem.Parameters[0].Name)
+		assert.Contains(t, getItem.Security, openapi.SecurityRequirement{})
+	}
+	// Every reference must point at a component.
+	b, _ := json.Marshal(doc)
+	for _, m := range regexp.MustCompile(`"\$ref":"#/components/schemas/(\w+)"`).FindAllStringSubmatch(string(b), -1) {
+		assert.Contains(t, doc.Components.Schemas, m[1])
+	}
+}
+
+func TestOpenAPICaseServed(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(echo.GET, "/api/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var doc openapi.Document
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.NotEmpty(t, doc.Paths["/items/{slug}"])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(echo.GET, "/api/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "openapi.json")
+}
+
+func TestOpenAPICaseStrictValidation(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	v1 := e.Group("/api")
+	v1.Use(middleware.OpenAPIWithConfig(middleware.OpenAPIConfig{Document: OpenAPI(), Prefix: "/api", AllowQuery: []string{"access_token"}}))
+	h.Register(v1)
+	do := func(method, target, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var m map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &m)
+		fields := map[string]interface{}{}
+		if errs, ok := m["errors"].(map[string]interface{}); ok && errs["fields"] != nil {
+			fields = errs["fields"].(map[string]interface{})
+		}
+		return rec.Code, fields
+	}
+	code := func(f interface{}) interface{} { return f.(map[string]interface{})["code"] }
+
+	status, fields := do(echo.POST, "/api/items", `{"item":{"title":"t","description":"d","body":"b","tagList":["Bad Tag"],"extra":1},"x":true}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "unknown", code(fields["/body/item/extra"]))
+	assert.Equal(t, "unknown", code(fields["/body/x"]))
+	assert.Equal(t, "invalid", code(fields["/body/item/tagList/0"]))
+
+	status, fields = do(echo.POST, "/api/items", `{"item":{"title":5,"body":"b"}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "type", code(fields["/body/item/title"]))
+	assert.Equal(t, "required", code(fields["/body/item/description"]))
+
+	status, _ = do(echo.POST, "/api/items", `{"item":`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, fields = do(echo.GET, "/api/items?limit=abc&sort=oldest&bogus=1", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "type", code(fields["/query/limit"]))
+	assert.Equal(t, "oneof", code(fields["/query/sort"]))
+	assert.Equal(t, "unknown", code(fields["/query/bogus"]))
+
+	status, _ = do(echo.GET, "/api/items?limit=5&access_token=x", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	// Empty strings after omitempty mean "unchanged", as for the validator.
+	status, _ = do(echo.PUT, "/api/player", `{"player":{"email":"player1@realworld.io","username":"","bio":"new bio"}}`)
+	assert.Equal(t, http.StatusOK, status)
+	status, fields = do(echo.PUT, "/api/player", `{"player":{"email":"player1@realworld.io","username":"ab"}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "min", code(fields["/body/player/username"]))
+
+	status, _ = do(echo.POST, "/api/items", `{"item":{"title":"strict","description":"d","body":"b","tagList":["go"]}}`)
+	assert.Equal(t, http.StatusCreated, status)
+}
@@ -1,117 +1,306 @@
 package handler
 
 import (
-	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"golang-starter-pack/model"
+	"golang-starter-pack/router/middleware"
 	"golang-starter-pack/utils"
-	"net/http"
 )
 
 func (h *Handler) SignUp(c echo.Context) error {
 	var u model.Player
 	req := &playerRegisterRequest{}
 	if err := req.bind(c, &u); err != nil {
-		fmt.Println("First IF")
-		// HERE!!!
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
 	}
-	if err := h.playerStore.Create(&u); err != nil {
-		fmt.Println("Second IF")
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	if err := h.players(c).Create(&u); err != nil {
+		h.log(c).Warn("sign up failed", "email", u.Email, "err", err)
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusCreated, newPlayerResponse(&u))
+	signups.Inc()
+	h.log(c).Info("player signed up", "player_id", u.ID)
+	r := newPlayerResponse(&u)
+	middleware.SetSessionCookies(c, r.Player.Token, utils.SessionTTL)
+	return c.JSON(http.StatusCreated, r)
 }
 
 func (h *Handler) Login(c echo.Context) error {
 	req := &playerLoginRequest{}
 	if err := req.bind(c); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
 	}
-	u, err := h.playerStore.GetByEmail(req.Player.Email)
+	now := time.Now()
+	n, err := h.players(c).CountFailedLoginsByIP(middleware.ClientIP(c), now.Add(-h.loginPolicy.IPWindow))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if n >= h.loginPolicy.IPMaxFailures {
+		setRetryAfter(c, h.loginPolicy.IPWindow)
+		return utils.RenderError(c, http.StatusTooManyRequests, utils.ErrTooManyAttempts())
+	}
+	u, err := h.players(c).GetByEmail(req.Player.Email)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusForbidden, utils.AccessForbidden())
+		return h.failUnknownLogin(c, req.Player.Email, req.Player.Password, now)
+	}
+	prev := *u
+	if ok, err := h.claimLoginAttempt(c, u, now, true); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if !ok {
+		return nil
 	}
 	if !u.CheckPassword(req.Player.Password) {
-		return c.JSON(http.StatusForbidden, utils.AccessForbidden())
+		if err := h.failLogin(c, u, req.Player.Email, now); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
 	}
-	return c.JSON(http.StatusOK, newPlayerResponse(u))
+	// With 2FA the password alone proves nothing yet: earlier failures are
+	// only forgiven once LoginTwoFactor accepts the second factor.
+	if u.TOTPEnabled {
+		if err := h.players(c).ReleaseLoginAttempt(u, &prev); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		return c.JSON(http.StatusOK, newLoginChallengeResponse(u))
+	}
+	if err := h.passLogin(c, u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	r := newPlayerResponse(u)
+	middleware.SetSessionCookies(c, r.Player.Token, utils.SessionTTL)
+	return c.JSON(http.StatusOK, r)
+}
+
+// Logout clears the session cookies. Tokens sent in headers are stateless
+// and stay valid until they expire.
+func (h *Handler) Logout(c echo.Context) error {
+	middleware.ClearSessionCookies(c)
+	return c.JSON(http.StatusOK, newResultResponse())
+}
+
+// claimLoginAttempt must precede every check of a password or second
+// factor. It answers 423 while u is locked out and, with backoff, 429 until
+// the delay earned by earlier failures has passed. Otherwise it counts the
+// attempt as failed up front, so that concurrent guesses cannot all pass
+// these checks on the same state: one claims the attempt and the others
+// answer 429. It reports false when it has answered.
+func (h *Handler) claimLoginAttempt(c echo.Context, u *model.Player, now time.Time, backoff bool) (bool, error) {
+	if h.loginBlocked(c, u.FailedLogins, u.LastFailedLoginAt, u.LockedUntil, now, backoff) {
+		return false, nil
+	}
+	var until *time.Time
+	if u.FailedLogins+1 >= h.loginPolicy.MaxFailures {
+		t := now.Add(h.loginPolicy.LockoutDuration)
+		until = &t
+	}
+	ok, err := h.players(c).ClaimLoginAttempt(u, now, until)
+	if err != nil || ok {
+		return ok, err
+	}
+	if !h.loginBlocked(c, u.FailedLogins, u.LastFailedLoginAt, u.LockedUntil, now, true) {
+		setRetryAfter(c, h.loginPolicy.BaseDelay)
+		utils.RenderError(c, http.StatusTooManyRequests, utils.ErrTooManyAttempts())
+	}
+	return false, nil
+}
+
+// loginBlocked answers 423 during a lockout and, with backoff, 429 within
+// the delay after the last failure, reporting whether it answered.
+func (h *Handler) loginBlocked(c echo.Context, failures int, last, lockedUntil *time.Time, now time.Time, backoff bool) bool {
+	if lockedUntil != nil && lockedUntil.After(now) {
+		setRetryAfter(c, lockedUntil.Sub(now))
+		utils.RenderError(c, http.StatusLocked, utils.ErrAccountLocked())
+		return true
+	}
+	if backoff && last != nil {
+		if wait := last.Add(h.loginPolicy.Delay(failures)).Sub(now); wait > 0 {
+			setRetryAfter(c, wait)
+			utils.RenderError(c, http.StatusTooManyRequests, utils.ErrTooManyAttempts())
+			return true
+		}
+	}
+	return false
+}
+
+// failUnknownLogin answers a login for an email without a player exactly as
+// a wrong password would be answered, so that responses do not reveal which
+// emails have accounts. The backoff and lockout are derived from the audit
+// trail, and a password is hashed to take as long as a real check.
+func (h *Handler) failUnknownLogin(c echo.Context, email, password string, now time.Time) error {
+	n, last, err := h.players(c).FailedLoginsByEmail(email)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	var lockedUntil *time.Time
+	if n >= h.loginPolicy.MaxFailures {
+		t := last.Add(h.loginPolicy.LockoutDuration)
+		lockedUntil = &t
+	}
+	if h.loginBlocked(c, n, last, lockedUntil, now, true) {
+		return nil
+	}
+	unknownPlayer.CheckPassword(password)
+	if err := h.failLogin(c, nil, email, now); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+}
+
+// unknownPlayer holds a password hash to check logins for unknown emails
+// against.
+var unknownPlayer = func() *model.Player {
+	u := &model.Player{}
+	u.Password, _ = u.HashPassword("unknown player")
+	return u
+}()
+
+// passLogin clears any backoff or lockout left by earlier failures.
+func (h *Handler) passLogin(c echo.Context, u *model.Player) error {
+	h.log(c).Info("login succeeded", "player_id", u.ID)
+	if u.FailedLogins == 0 && u.LockedUntil == nil {
+		return nil
+	}
+	u.FailedLogins = 0
+	u.LastFailedLoginAt = nil
+	u.LockedUntil = nil
+	return h.players(c).UpdateLoginState(u)
+}
+
+// failLogin records a failed attempt in the audit trail. The player's own
+// count was already advanced by claimLoginAttempt.
+func (h *Handler) failLogin(c echo.Context, u *model.Player, email string, now time.Time) error {
+	a := &model.LoginAttempt{Email: email, IP: middleware.ClientIP(c)}
+	h.log(c).Info("login failed", "email", email)
+	if u != nil {
+		a.PlayerID = &u.ID
+		if u.IsLocked(now) {
+			h.log(c).Warn("account locked", "player_id", u.ID, "until", *u.LockedUntil)
+		}
+	}
+	return h.players(c).AddLoginAttempt(a)
+}
+
+func setRetryAfter(c echo.Context, d time.Duration) {
+	c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
 }
 
 func (h *Handler) CurrentPlayer(c echo.Context) error {
-	u, err := h.playerStore.GetByID(playerIDFromToken(c))
+	u, err := h.players(c).GetByID(playerIDFromToken(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
 	return c.JSON(http.StatusOK, newPlayerResponse(u))
 }
 
 func (h *Handler) UpdatePlayer(c echo.Context) error {
-	u, err := h.playerStore.GetByID(playerIDFromToken(c))
+	return h.updatePlayer(c, false)
+}
+
+// PatchPlayer updates only the members named by an RFC 7396 merge patch or
+// RFC 6902 JSON patch.
+func (h *Handler) PatchPlayer(c echo.Context) error {
+	return h.updatePlayer(c, true)
+}
+
+func (h *Handler) updatePlayer(c echo.Context, patch bool) error {
+	u, err := h.players(c).GetByID(playerIDFromToken(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	if _, etag, err := h.profileRepresentation(c, u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if err := utils.CheckIfMatch(c, etag); err != nil {
+		return utils.RenderError(c, http.StatusPreconditionFailed, err)
 	}
 	req := newPlayerUpdateRequest()
 	req.populate(u)
-	if err := req.bind(c, u); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	bind := req.bind
+	if patch {
+		bind = req.patch
+	}
+	if err := bind(c, u); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+	if err := h.players(c).Update(u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	if err := h.playerStore.Update(u); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	// The ETag is the profile's, which If-Match is checked against.
+	_, etag, err := h.profileRepresentation(c, u)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
+	c.Response().Header().Set("ETag", etag)
 	return c.JSON(http.StatusOK, newPlayerResponse(u))
 }
 
 func (h *Handler) GetProfile(c echo.Context) error {
 	username := c.Param("username")
-	u, err := h.playerStore.GetByUsername(username)
+	u, err := h.players(c).GetByUsername(username)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	r, etag, err := h.profileRepresentation(c, u)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newProfileResponse(h.playerStore, playerIDFromToken(c), u))
+	if utils.NotModified(c, etag) {
+		return nil
+	}
+	return c.JSON(http.StatusOK, r)
+}
+
+// profileRepresentation builds u's profile for the viewer and its ETag.
+func (h *Handler) profileRepresentation(c echo.Context, u *model.Player) (*profileResponse, string, error) {
+	r := newProfileResponse(h.players(c), playerIDFromToken(c), u)
+	etag, err := representationETag(c, r)
+	return r, etag, err
 }
 
 func (h *Handler) Follow(c echo.Context) error {
 	followerID := playerIDFromToken(c)
 	username := c.Param("username")
-	u, err := h.playerStore.GetByUsername(username)
+	u, err := h.players(c).GetByUsername(username)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	if err := h.playerStore.AddFollower(u, followerID); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	if err := h.players(c).AddFollower(u, followerID); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newProfileResponse(h.playerStore, playerIDFromToken(c), u))
+	return c.JSON(http.StatusOK, newProfileResponse(h.players(c), playerIDFromToken(c), u))
 }
 func (h *Handler) Unfollow(c echo.Context) error {
 	followerID := playerIDFromToken(c)
 	username := c.Param("username")
-	u, err := h.playerStore.GetByUsername(username)
+	u, err := h.players(c).GetByUsername(username)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.NewError(err))
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
 	if u == nil {
-		return c.JSON(http.StatusNotFound, utils.NotFound())
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
 	}
-	if err := h.playerStore.RemoveFollower(u, followerID); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewError(err))
+	if err := h.players(c).RemoveFollower(u, followerID); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, newProfileResponse(h.playerStore, playerIDFromToken(c), u))
+	return c.JSON(http.StatusOK, newProfileResponse(h.players(c), playerIDFromToken(c), u))
 }
 func playerIDFromToken(c echo.Context) uint {
 	id, ok := c.Get("player").(uint)
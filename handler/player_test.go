@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"golang-starter-pack/model"
+	"golang-starter-pack/player"
+	"golang-starter-pack/router"
 	"golang-starter-pack/router/middleware"
 	"golang-starter-pack/utils"
 
@@ -17,7 +23,7 @@ func TestSignUpCaseSuccess(t *testing.T) {
 	tearDown()
 	setup()
 	var (
-		reqJSON = `{"player":{"username":"alice","email":"alice@realworld.io","password":"secret"}}`
+		reqJSON = `{"player":{"username":"alice","email":"alice@realworld.io","password":"secret123"}}`
 	)
 	req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -265,3 +271,493 @@ func TestUnfollow(t *testing.T) {
 		assert.Equal(t, false, m["following"])
 	}
 }
+
+func TestLoginCaseLockout(t *testing.T) {
+	tearDown()
+	setup()
+	h.loginPolicy.BaseDelay = 0
+	h.loginPolicy.MaxFailures = 3
+	defer func() { h.loginPolicy = utils.DefaultLoginPolicy }()
+	login := func(password string) *httptest.ResponseRecorder {
+		reqJSON := `{"player":{"email":"player1@realworld.io","password":"` + password + `"}}`
+		req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(reqJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, h.Login(c))
+		return rec
+	}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusForbidden, login("wrong").Code)
+	}
+	rec := login("secret")
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	var count int
+	d.Model(&model.LoginAttempt{}).Count(&count)
+	assert.Equal(t, 3, count)
+
+	d.Model(&model.Player{}).Where("id = ?", 2).Update("admin", true)
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.POST, "/api/admin/players/:username/unlock", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(2)))
+	rec = httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("username")
+	c.SetParamValues("player1")
+	err := jwtMiddleware(h.adminOnly(h.UnlockPlayer))(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, http.StatusOK, login("secret").Code)
+}
+
+// racingPlayerStore claims a login attempt behind the caller's back after
+// each GetByEmail, as a concurrent request would.
+type racingPlayerStore struct {
+	player.Store
+}
+
+func (s *racingPlayerStore) GetByEmail(email string) (*model.Player, error) {
+	u, err := s.Store.GetByEmail(email)
+	if u != nil {
+		other := *u
+		_, err = s.Store.ClaimLoginAttempt(&other, time.Now(), nil)
+	}
+	return u, err
+}
+
+func TestLoginCaseConcurrentGuess(t *testing.T) {
+	tearDown()
+	setup()
+	h := NewHandler(&racingPlayerStore{Store: us}, as)
+	h.loginPolicy.BaseDelay = 0
+	req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"player1@realworld.io","password":"wrong"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h.Login(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	u, _ := us.GetByID(1)
+	assert.Equal(t, 1, u.FailedLogins)
+}
+
+func TestLoginCaseUnknownEmail(t *testing.T) {
+	tearDown()
+	setup()
+	h.loginPolicy.MaxFailures = 3
+	defer func() { h.loginPolicy = utils.DefaultLoginPolicy }()
+	login := func(email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"`+email+`","password":"wrong"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderXForwardedFor, "10.0.0.9")
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h.Login(e.NewContext(req, rec)))
+		return rec
+	}
+	// An unknown email is throttled and locked like an account, so the
+	// answers do not tell them apart.
+	for _, email := range []string{"player1@realworld.io", "nobody@realworld.io"} {
+		assert.Equal(t, http.StatusForbidden, login(email).Code)
+		rec := login(email)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, email)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"), email)
+	}
+	h.loginPolicy.BaseDelay = 0
+	for _, email := range []string{"player1@realworld.io", "nobody@realworld.io"} {
+		assert.Equal(t, http.StatusForbidden, login(email).Code)
+		assert.Equal(t, http.StatusForbidden, login(email).Code)
+		rec := login(email)
+		assert.Equal(t, http.StatusLocked, rec.Code, email)
+		assert.Equal(t, "900", rec.Header().Get("Retry-After"), email)
+	}
+
+	// Forwarding headers are only believed from trusted proxies.
+	var direct, proxied model.LoginAttempt
+	d.Last(&direct)
+	assert.Equal(t, "192.0.2.1", direct.IP)
+	assert.NoError(t, middleware.SetTrustedProxies([]string{"192.0.2.0/24"}))
+	defer middleware.SetTrustedProxies(nil)
+	login("other@realworld.io")
+	d.Last(&proxied)
+	assert.Equal(t, "10.0.0.9", proxied.IP)
+}
+
+func TestUnlockPlayerCaseNotAdmin(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.POST, "/api/admin/players/:username/unlock", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("username")
+	c.SetParamValues("player1")
+	err := jwtMiddleware(h.adminOnly(h.UnlockPlayer))(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestCookieSessionCSRF(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"player1@realworld.io","password":"secret"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	var session, csrf *http.Cookie
+	for _, ck := range rec.Result().Cookies() {
+		switch ck.Name {
+		case middleware.SessionCookie:
+			session = ck
+		case middleware.CSRFCookie:
+			csrf = ck
+		}
+	}
+	if !assert.NotNil(t, session) || !assert.NotNil(t, csrf) {
+		return
+	}
+	assert.True(t, session.HttpOnly)
+
+	send := func(method, path, body, csrfHeader string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.AddCookie(session)
+		req.AddCookie(csrf)
+		if csrfHeader != "" {
+			req.Header.Set(middleware.CSRFHeader, csrfHeader)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, send(echo.GET, "/api/player", "", ""))
+	assert.Equal(t, http.StatusForbidden, send(echo.PUT, "/api/player", `{"player":{"bio":"x"}}`, ""))
+	assert.Equal(t, http.StatusForbidden, send(echo.PUT, "/api/player", `{"player":{"bio":"x"}}`, "forged"))
+	assert.Equal(t, http.StatusOK, send(echo.PUT, "/api/player", `{"player":{"bio":"x"}}`, csrf.Value))
+
+	req = httptest.NewRequest(echo.GET, "/api/player", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+session.Value)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(echo.GET, "/api/player?access_token="+session.Value, nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(echo.GET, "/api/player?access_token="+session.Value, nil)
+	req.Header.Set(echo.HeaderUpgrade, "websocket")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestSignUpCaseUsernameTaken(t *testing.T) {
+	tearDown()
+	setup()
+	reqJSON := `{"player":{"username":"player1","email":"other@realworld.io","password":"secret123"}}`
+	req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, h.SignUp(c))
+	if assert.Equal(t, http.StatusConflict, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeConflict, res.Errors.Code)
+		assert.Equal(t, "username already taken", res.Errors.Message)
+		assert.Equal(t, "taken", res.Errors.Fields["username"].Code)
+	}
+}
+
+func TestSignUpCaseValidation(t *testing.T) {
+	tearDown()
+	setup()
+	reqJSON := `{"player":{"username":"alice","email":"not-an-email"}}`
+	req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, h.SignUp(c))
+	if assert.Equal(t, http.StatusUnprocessableEntity, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeValidation, res.Errors.Code)
+		assert.Equal(t, "email", res.Errors.Fields["email"].Code)
+		assert.Equal(t, "required", res.Errors.Fields["password"].Code)
+	}
+}
+
+func TestUnknownRouteErrorShape(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+	req := httptest.NewRequest(echo.GET, "/api/nope", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if assert.Equal(t, http.StatusNotFound, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeNotFound, res.Errors.Code)
+	}
+}
+
+func TestSignUpCaseValidationLocalized(t *testing.T) {
+	tearDown()
+	setup()
+	reqJSON := `{"player":{"username":"alice","email":"alice@realworld.io"}}`
+	req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "fr;q=0.9, es-MX, en;q=0.5")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, h.SignUp(c))
+	if assert.Equal(t, http.StatusUnprocessableEntity, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "la validación falló", res.Errors.Message)
+		assert.Equal(t, "es obligatorio", res.Errors.Fields["password"].Message)
+	}
+
+	utils.Messages.Add("fr", utils.Catalog{"validation.required": "est obligatoire"})
+	req = httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "fr-CA")
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.NoError(t, h.SignUp(c))
+	var res utils.Error
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "est obligatoire", res.Errors.Fields["password"].Message)
+	assert.Equal(t, "validation failed", res.Errors.Message)
+}
+
+func TestSignUpCaseRules(t *testing.T) {
+	tearDown()
+	setup()
+	cases := map[string]string{
+		"admin":  `{"player":{"username":"admin","email":"a@realworld.io","password":"secret123"}}`,
+		"a b":    `{"player":{"username":"a b","email":"a@realworld.io","password":"secret123"}}`,
+		"weakpw": `{"player":{"username":"alice","email":"a@realworld.io","password":"password"}}`,
+	}
+	fields := map[string]string{"admin": "notreserved", "a b": "username", "weakpw": "password"}
+	for name, reqJSON := range cases {
+		req := httptest.NewRequest(echo.POST, "/api/players", strings.NewReader(reqJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, h.SignUp(c))
+		if assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, name) {
+			var res utils.Error
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			code := res.Errors.Fields["username"].Code
+			if name == "weakpw" {
+				code = res.Errors.Fields["password"].Code
+			}
+			assert.Equal(t, fields[name], code, name)
+		}
+	}
+}
+
+func TestPatchPlayerCaseSuccess(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.PATCH, "/api/player", strings.NewReader(`{"player":{"bio":"patched bio"}}`))
+	req.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.PatchPlayer)(c))
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		m := responseMap(rec.Body.Bytes(), "player")
+		assert.Equal(t, "player1", m["username"])
+		assert.Equal(t, "player1@realworld.io", m["email"])
+		assert.Equal(t, "patched bio", m["bio"])
+		assert.Equal(t, "http://realworld.io/player1.jpg", m["image"])
+	}
+}
+
+func TestUpdatePlayerCaseLegacyUsername(t *testing.T) {
+	tearDown()
+	setup()
+	assert.NoError(t, d.Model(&model.Player{}).Where("id = ?", 1).UpdateColumn("username", "admin").Error)
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	update := func(method, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/player", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if method == echo.PATCH {
+			assert.NoError(t, jwtMiddleware(h.PatchPlayer)(c))
+		} else {
+			assert.NoError(t, jwtMiddleware(h.UpdatePlayer)(c))
+		}
+		return rec
+	}
+
+	// A name the rules now reserve is kept through unrelated edits.
+	rec := update(echo.PUT, echo.MIMEApplicationJSON, `{"player":{"bio":"put bio"}}`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "admin", responseMap(rec.Body.Bytes(), "player")["username"])
+	}
+	rec = update(echo.PATCH, utils.MIMEMergePatch, `{"player":{"username":"admin","bio":"patched bio"}}`)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "patched bio", responseMap(rec.Body.Bytes(), "player")["bio"])
+	}
+
+	// A new name must follow the rules.
+	assert.Equal(t, http.StatusUnprocessableEntity, update(echo.PUT, echo.MIMEApplicationJSON, `{"player":{"username":"root"}}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, update(echo.PUT, echo.MIMEApplicationJSON, `{"player":{"username":"bad name"}}`).Code)
+	u, _ := us.GetByID(1)
+	assert.Equal(t, "admin", u.Username)
+}
+
+func TestPatchPlayerCasePassword(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	patch := func(contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.PATCH, "/api/player", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		rec := httptest.NewRecorder()
+		assert.NoError(t, jwtMiddleware(h.PatchPlayer)(e.NewContext(req, rec)))
+		return rec
+	}
+
+	// The stored hash is not part of the document patches run against, so
+	// it can be neither copied out nor tested against guesses.
+	rec := patch(utils.MIMEJSONPatch, `[{"op":"copy","from":"/player/password","path":"/player/bio"}]`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	u, _ := us.GetByID(1)
+	assert.Equal(t, "player1 bio", *u.Bio)
+	right := patch(utils.MIMEJSONPatch, `[{"op":"test","path":"/player/password","value":"`+u.Password+`"}]`)
+	wrong := patch(utils.MIMEJSONPatch, `[{"op":"test","path":"/player/password","value":"guess"}]`)
+	assert.Equal(t, http.StatusBadRequest, right.Code)
+	assert.Equal(t, wrong.Code, right.Code)
+
+	// Patches may still set a new password, and leave it alone otherwise.
+	assert.Equal(t, http.StatusOK, patch(utils.MIMEMergePatch, `{"player":{"bio":"new bio"}}`).Code)
+	u, _ = us.GetByID(1)
+	assert.True(t, u.CheckPassword("secret"))
+	assert.Equal(t, http.StatusOK, patch(utils.MIMEMergePatch, `{"player":{"password":"newsecret1"}}`).Code)
+	u, _ = us.GetByID(1)
+	assert.True(t, u.CheckPassword("newsecret1"))
+}
+
+func TestUpdatePlayerCaseStaleETag(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	profile := func(viewer uint, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.GET, "/api/profiles/:username", nil)
+		req.Header = header
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(viewer)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("username")
+		c.SetParamValues("player1")
+		assert.NoError(t, jwtMiddleware(h.GetProfile)(c))
+		return rec
+	}
+	etag := profile(1, http.Header{}).Header().Get("ETag")
+	update := func(ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.PUT, "/api/player", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+		req.Header.Set("If-Match", ifMatch)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, jwtMiddleware(h.UpdatePlayer)(c))
+		return rec
+	}
+	rec := update(etag, `{"player":{"bio":"first"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	assert.Equal(t, http.StatusPreconditionFailed, update(etag, `{"player":{"bio":"second"}}`).Code)
+
+	// The tag follows the viewer's following flag and the follower count.
+	etag = rec.Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, profile(1, http.Header{"If-None-Match": {etag}}).Code)
+	u, _ := us.GetByID(1)
+	assert.NoError(t, us.AddFollower(u, 2))
+	assert.Equal(t, http.StatusOK, profile(1, http.Header{"If-None-Match": {etag}}).Code)
+	etag = profile(1, http.Header{}).Header().Get("ETag")
+	assert.NotEqual(t, etag, profile(2, http.Header{}).Header().Get("ETag"))
+}
+
+func TestLoginCaseRateLimited(t *testing.T) {
+	tearDown()
+	setup()
+	h.loginPolicy.BaseDelay = 0
+	defer func() { h.loginPolicy = utils.DefaultLoginPolicy }()
+	e := router.New()
+	h.Register(e.Group("/api"))
+	login := func(remote, forwarded string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"nobody@realworld.io","password":"secret"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderOrigin, "https://app.example")
+		req.RemoteAddr = remote + ":1234"
+		req.Header.Set(echo.HeaderXRealIP, forwarded)
+		req.Header.Set(echo.HeaderXForwardedFor, forwarded)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	for i := 0; i < authRateLimit.Burst; i++ {
+		rec := login("10.0.0.1", "10.0.0.1")
+		assert.NotEqual(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "5", rec.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(authRateLimit.Burst-i-1), rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	rec := login("10.0.0.1", "10.0.0.1")
+	if assert.Equal(t, http.StatusTooManyRequests, rec.Code) {
+		assert.Equal(t, "12", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Reset"))
+		// Browsers only let cross-origin scripts read exposed headers.
+		exposed := rec.Header().Get(echo.HeaderAccessControlExposeHeaders)
+		for _, name := range []string{"Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"} {
+			assert.Contains(t, exposed, name)
+		}
+		m := responseMap(rec.Body.Bytes(), "errors")
+		assert.Equal(t, "too_many_requests", m["code"])
+		assert.Equal(t, "rate limit exceeded", m["message"])
+	}
+	// Forged forwarding headers do not buy a fresh bucket.
+	assert.Equal(t, http.StatusTooManyRequests, login("10.0.0.1", "10.0.0.2").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, login("10.0.0.2", "10.0.0.1").Code)
+
+	// Behind a trusted proxy the forwarded address is the client's.
+	assert.NoError(t, middleware.SetTrustedProxies([]string{"10.0.0.1"}))
+	defer middleware.SetTrustedProxies(nil)
+	assert.NotEqual(t, http.StatusTooManyRequests, login("10.0.0.1", "10.0.0.3").Code)
+}
+
+func TestCurrentPlayerCaseMissingToken(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.GET, "/api/player", nil)
+	req.Header.Set("Accept-Language", "es")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err := jwtMiddleware(func(context echo.Context) error {
+		return h.CurrentPlayer(c)
+	})(c)
+	assert.NoError(t, err)
+	if assert.Equal(t, http.StatusUnauthorized, rec.Code) {
+		var res utils.Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, utils.CodeUnauthorized, res.Errors.Code)
+		assert.Equal(t, "jwt ausente o mal formado", res.Errors.Message)
+	}
+}
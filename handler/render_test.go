@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/render"
+	"golang-starter-pack/router"
+	"golang-starter-pack/router/middleware"
+)
+
+func TestRenderFormats(t *testing.T) {
+	tearDown()
+	setup()
+	e := router.New()
+	e.Pre(middleware.StripAPIVersion("/api"))
+	h.Register(e.Group("/api"))
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(echo.GET, path, nil)
+		req.Header.Set(echo.HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var tags tagListResponse
+	rec := get("/api/tags", "")
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header()[echo.HeaderVary], echo.HeaderAccept)
+	if !assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tags)) || !assert.Len(t, tags.Tags, 2) {
+		return
+	}
+	t0, t1 := tags.Tags[0], tags.Tags[1]
+
+	rec = get("/api/tags", "application/x-msgpack")
+	assert.Equal(t, render.MIMEMsgPack, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "\x81\xa4tags\x92\xa4"+t0+"\xa4"+t1, rec.Body.String())
+
+	rec = get("/api/tags", "application/json;q=0.5, application/cbor")
+	assert.Equal(t, render.MIMECBOR, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "\xa1\x64tags\x82\x64"+t0+"\x64"+t1, rec.Body.String())
+
+	rec = get("/api/tags", "text/csv")
+	assert.Equal(t, "text/csv; charset=UTF-8; header=present", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "tag\n"+t0+"\n"+t1+"\n", rec.Body.String())
+
+	rec = get("/api/items", "text/csv, application/json;q=0.9")
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if assert.NoError(t, err) && assert.Len(t, rows, 3) {
+		assert.Equal(t, itemColumns, rows[0])
+		for _, row := range rows[1:] {
+			if row[0] == "item1-slug" {
+				assert.Equal(t, []string{"tag1,tag2", "1", "player1"}, []string{row[4], row[9], row[10]})
+			}
+		}
+	}
+	rec = get("/api/v2/items", "text/csv")
+	if rows, err = csv.NewReader(rec.Body).ReadAll(); assert.NoError(t, err) && assert.NotEmpty(t, rows) {
+		assert.Equal(t, []string{"stats.favorites", "stats.comments"}, rows[0][8:10])
+	}
+
+	// CSV is only offered for lists; other responses fall back to JSON.
+	rec = get("/api/items/item1-slug", "text/csv")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	rec = get("/api/items/item1-slug", "application/vnd.starter.v2+json")
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"stats"`)
+
+	// Errors are rendered like any other response.
+	rec = get("/api/items/nope", "application/msgpack")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, render.MIMEMsgPack, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "\xa6errors")
+}
+
+func TestRenderNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		v             interface{}
+		msgpack, cbor string
+	}{
+		{0, "\x00", "\x00"},
+		{127, "\x7f", "\x18\x7f"},
+		{200, "\xcc\xc8", "\x18\xc8"},
+		{70000, "\xce\x00\x01\x11\x70", "\x1a\x00\x01\x11\x70"},
+		{-1, "\xff", "\x20"},
+		{-100, "\xd0\x9c", "\x38\x63"},
+		{uint64(1 << 63), "\xcf\x80\x00\x00\x00\x00\x00\x00\x00", "\x1b\x80\x00\x00\x00\x00\x00\x00\x00"},
+		{1.5, "\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00", "\xfb\x3f\xf8\x00\x00\x00\x00\x00\x00"},
+		{nil, "\xc0", "\xf6"},
+		{true, "\xc3", "\xf5"},
+		{strings.Repeat("a", 40), "\xd9\x28" + strings.Repeat("a", 40), "\x78\x28" + strings.Repeat("a", 40)},
+	} {
+		b, err := render.MarshalMsgPack(tc.v)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.msgpack, string(b), "msgpack %v", tc.v)
+		}
+		b, err = render.MarshalCBOR(tc.v)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.cbor, string(b), "cbor %v", tc.v)
+		}
+	}
+}
@@ -1,18 +1,27 @@
 package handler
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
 	"github.com/gosimple/slug"
 	"github.com/labstack/echo/v4"
+	"golang-starter-pack/item"
 	"golang-starter-pack/model"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
 )
 
 type playerUpdateRequest struct {
 	Player struct {
-		Username string `json:"username"`
+		Username string `json:"username" validate:"omitempty,min=3,max=32,username,notreserved"`
 		Email    string `json:"email" validate:"email"`
-		Password string `json:"password"`
-		Bio      string `json:"bio"`
-		Image    string `json:"image"`
+		Password string `json:"password,omitempty" validate:"omitempty,password"`
+		Bio      string `json:"bio" validate:"max=1000"`
+		Image    string `json:"image" validate:"omitempty,url,max=2048"`
 	} `json:"player"`
 }
 
@@ -20,10 +29,12 @@ func newPlayerUpdateRequest() *playerUpdateRequest {
 	return new(playerUpdateRequest)
 }
 
+// populate fills r with the player's current profile. The password is
+// left empty, meaning unchanged, so that patches can neither read nor test
+// the stored hash.
 func (r *playerUpdateRequest) populate(u *model.Player) {
 	r.Player.Username = u.Username
 	r.Player.Email = u.Email
-	r.Player.Password = u.Password
 	if u.Bio != nil {
 		r.Player.Bio = *u.Bio
 	}
@@ -36,12 +47,38 @@ func (r *playerUpdateRequest) bind(c echo.Context, u *model.Player) error {
 	if err := c.Bind(r); err != nil {
 		return err
 	}
-	if err := c.Validate(r); err != nil {
+	return r.apply(c, u)
+}
+
+// patch applies the merge patch or JSON patch in the request body to the
+// populated request, so only the members it names change.
+func (r *playerUpdateRequest) patch(c echo.Context, u *model.Player) error {
+	if err := applyPatch(c, r); err != nil {
 		return err
 	}
+	return r.apply(c, u)
+}
+
+// apply validates r and copies it onto u. The username is only validated
+// when it changes, so players named before the current rules can still
+// edit the rest of their profile. Changing the email or password takes
+// over the account, so API keys, even with profile:write, may not.
+func (r *playerUpdateRequest) apply(c echo.Context, u *model.Player) error {
+	username := r.Player.Username
+	if username == u.Username {
+		r.Player.Username = ""
+	}
+	err := c.Validate(r)
+	r.Player.Username = username
+	if err != nil {
+		return err
+	}
+	if (r.Player.Email != u.Email || r.Player.Password != "") && !middleware.HasSession(c) {
+		return middleware.ErrSessionRequired
+	}
 	u.Username = r.Player.Username
 	u.Email = r.Player.Email
-	if r.Player.Password != u.Password {
+	if r.Player.Password != "" {
 		h, err := u.HashPassword(r.Player.Password)
 		if err != nil {
 			return err
@@ -55,9 +92,9 @@ func (r *playerUpdateRequest) bind(c echo.Context, u *model.Player) error {
 
 type playerRegisterRequest struct {
 	Player struct {
-		Username string `json:"username" validate:"required"`
+		Username string `json:"username" validate:"required,min=3,max=32,username,notreserved"`
 		Email    string `json:"email" validate:"required,email"`
-		Password string `json:"password" validate:"required"`
+		Password string `json:"password" validate:"required,password"`
 	} `json:"player"`
 }
 
@@ -97,10 +134,10 @@ func (r *playerLoginRequest) bind(c echo.Context) error {
 
 type itemCreateRequest struct {
 	Items struct {
-		Title       string   `json:"title" validate:"required"`
-		Description string   `json:"description" validate:"required"`
-		Body        string   `json:"body" validate:"required"`
-		Tags        []string `json:"tagList, omitempty"`
+		Title       string   `json:"title" validate:"required,max=200"`
+		Description string   `json:"description" validate:"required,max=500"`
+		Body        string   `json:"body" validate:"required,max=65536"`
+		Tags        []string `json:"tagList,omitempty" validate:"max=10,dive,tag"`
 	} `json:"item"`
 }
 
@@ -108,6 +145,10 @@ func (r *itemCreateRequest) bind(c echo.Context, a *model.Item) error {
 	if err := c.Bind(r); err != nil {
 		return err
 	}
+	return r.apply(c, a)
+}
+
+func (r *itemCreateRequest) apply(c echo.Context, a *model.Item) error {
 	if err := c.Validate(r); err != nil {
 		return err
 	}
@@ -125,28 +166,48 @@ func (r *itemCreateRequest) bind(c echo.Context, a *model.Item) error {
 
 type itemUpdateRequest struct {
 	Items struct {
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Body        string   `json:"body"`
-		Tags        []string `json:"tagList"`
+		Title       string   `json:"title" validate:"required,max=200"`
+		Description string   `json:"description" validate:"max=500"`
+		Body        string   `json:"body" validate:"max=65536"`
+		Tags        []string `json:"tagList" validate:"max=10,dive,tag"`
 	} `json:"item"`
 }
 
+// populate fills the request with the item's current state, tags
+// included, so that fields left out of the body keep their values.
 func (r *itemUpdateRequest) populate(a *model.Item) {
 	r.Items.Title = a.Title
 	r.Items.Description = a.Description
 	r.Items.Body = a.Body
+	r.Items.Tags = make([]string, 0, len(a.Tags))
+	for _, t := range a.Tags {
+		r.Items.Tags = append(r.Items.Tags, t.Tag)
+	}
 }
 
 func (r *itemUpdateRequest) bind(c echo.Context, a *model.Item) error {
 	if err := c.Bind(r); err != nil {
 		return err
 	}
+	return r.apply(c, a)
+}
+
+func (r *itemUpdateRequest) patch(c echo.Context, a *model.Item) error {
+	if err := applyPatch(c, r); err != nil {
+		return err
+	}
+	return r.apply(c, a)
+}
+
+func (r *itemUpdateRequest) apply(c echo.Context, a *model.Item) error {
 	if err := c.Validate(r); err != nil {
 		return err
 	}
+	// Keep existing links working unless the title actually changed.
+	if r.Items.Title != a.Title {
+		a.Slug = slug.Make(r.Items.Title)
+	}
 	a.Title = r.Items.Title
-	a.Slug = slug.Make(a.Title)
 	a.Description = r.Items.Description
 	a.Body = r.Items.Body
 	return nil
@@ -154,7 +215,7 @@ func (r *itemUpdateRequest) bind(c echo.Context, a *model.Item) error {
 
 type createCommentRequest struct {
 	Comment struct {
-		Body string `json:"body" validate:"required"`
+		Body string `json:"body" validate:"required,max=8192"`
 	} `json:"comment"`
 }
 
@@ -162,6 +223,10 @@ func (r *createCommentRequest) bind(c echo.Context, cm *model.Comment) error {
 	if err := c.Bind(r); err != nil {
 		return err
 	}
+	return r.apply(c, cm)
+}
+
+func (r *createCommentRequest) apply(c echo.Context, cm *model.Comment) error {
 	if err := c.Validate(r); err != nil {
 		return err
 	}
@@ -169,3 +234,124 @@ func (r *createCommentRequest) bind(c echo.Context, cm *model.Comment) error {
 	cm.PlayerID = playerIDFromToken(c)
 	return nil
 }
+
+type twoFactorVerifyRequest struct {
+	TwoFactor struct {
+		Code string `json:"code" validate:"required"`
+	} `json:"twoFactor"`
+}
+
+func (r *twoFactorVerifyRequest) bind(c echo.Context) error {
+	if err := c.Bind(r); err != nil {
+		return err
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+type twoFactorLoginRequest struct {
+	TwoFactor struct {
+		Challenge string `json:"challenge" validate:"required"`
+		Code      string `json:"code" validate:"required"`
+	} `json:"twoFactor"`
+}
+
+func (r *twoFactorLoginRequest) bind(c echo.Context) error {
+	if err := c.Bind(r); err != nil {
+		return err
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+type twoFactorDisableRequest struct {
+	TwoFactor struct {
+		Password string `json:"password" validate:"required"`
+		Code     string `json:"code" validate:"required"`
+	} `json:"twoFactor"`
+}
+
+func (r *twoFactorDisableRequest) bind(c echo.Context) error {
+	if err := c.Bind(r); err != nil {
+		return err
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+type apiKeyCreateRequest struct {
+	APIKey struct {
+		Name   string   `json:"name" validate:"required"`
+		Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=items:read items:write comments:write profile:write"`
+	} `json:"apiKey"`
+}
+
+func (r *apiKeyCreateRequest) bind(c echo.Context, k *model.APIKey) error {
+	if err := c.Bind(r); err != nil {
+		return err
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+	k.Name = r.APIKey.Name
+	k.SetScopes(r.APIKey.Scopes)
+	k.PlayerID = playerIDFromToken(c)
+	return nil
+}
+
+type itemListQuery struct {
+	Sort string `json:"sort" validate:"omitempty,oneof=recent favorites comments"`
+}
+
+// listOrder reads the sort query parameter of item lists.
+func listOrder(c echo.Context) (item.Order, error) {
+	q := itemListQuery{Sort: c.QueryParam("sort")}
+	if err := c.Validate(&q); err != nil {
+		return "", err
+	}
+	if q.Sort == "" {
+		return item.OrderRecent, nil
+	}
+	return item.Order(q.Sort), nil
+}
+
+var errUnsupportedPatch = echo.NewHTTPError(http.StatusUnsupportedMediaType,
+	"patch must be "+utils.MIMEMergePatch+" or "+utils.MIMEJSONPatch)
+
+// applyPatch patches the JSON form of r with the request body, chosen by
+// Content-Type, and decodes the result back into r.
+func applyPatch(c echo.Context, r interface{}) error {
+	doc, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	ctype := c.Request().Header.Get(echo.HeaderContentType)
+	if i := strings.Index(ctype, ";"); i >= 0 {
+		ctype = ctype[:i]
+	}
+	switch strings.TrimSpace(ctype) {
+	case utils.MIMEMergePatch:
+		doc, err = utils.MergePatch(doc, body)
+	case utils.MIMEJSONPatch:
+		doc, err = utils.JSONPatch(doc, body)
+	default:
+		return errUnsupportedPatch
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	// Reset first so members removed by the patch do not keep old values.
+	v := reflect.ValueOf(r).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(doc, r)
+}
@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"golang-starter-pack/cache"
+	"golang-starter-pack/item"
 	"golang-starter-pack/model"
 	"golang-starter-pack/player"
+	"golang-starter-pack/render"
+	"golang-starter-pack/router/middleware"
 	"golang-starter-pack/utils"
 )
 
@@ -29,12 +36,57 @@ func newPlayerResponse(u *model.Player) *playerResponse {
 	return r
 }
 
+// representationETag tags v as this request receives it. v carries the
+// counters and the viewer's following and favorited flags; the API version
+// and negotiated format are added, so that no viewer, version or format is
+// answered 304 for a body rendered for another.
+func representationETag(c echo.Context, v interface{}) (string, error) {
+	f := render.Negotiate(c.Request().Header.Get(echo.HeaderAccept), v)
+	if f == nil {
+		f = render.JSON
+	}
+	return utils.RepresentationETag(fmt.Sprintf("v%d:%s", middleware.APIVersionOf(c), f.Name), v)
+}
+
+type loginChallengeResponse struct {
+	Challenge struct {
+		Token     string `json:"token"`
+		ExpiresIn int    `json:"expiresIn"`
+	} `json:"challenge"`
+}
+
+func newLoginChallengeResponse(u *model.Player) *loginChallengeResponse {
+	r := new(loginChallengeResponse)
+	r.Challenge.Token = utils.GenerateChallengeJWT(u.ID)
+	r.Challenge.ExpiresIn = 300
+	return r
+}
+
+type twoFactorSetupResponse struct {
+	TwoFactor struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+	} `json:"twoFactor"`
+}
+
+func newTwoFactorSetupResponse(u *model.Player) *twoFactorSetupResponse {
+	r := new(twoFactorSetupResponse)
+	r.TwoFactor.Secret = *u.TOTPSecret
+	r.TwoFactor.URI = utils.TOTPURI(u.Email, *u.TOTPSecret)
+	return r
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 type profileResponse struct {
 	Profile struct {
-		Username  string  `json:"username"`
-		Bio       *string `json:"bio"`
-		Image     *string `json:"image"`
-		Following bool    `json:"following"`
+		Username       string  `json:"username"`
+		Bio            *string `json:"bio"`
+		Image          *string `json:"image"`
+		Following      bool    `json:"following"`
+		FollowersCount int     `json:"followersCount"`
 	} `json:"profile"`
 }
 
@@ -44,6 +96,7 @@ func newProfileResponse(us player.Store, playerID uint, u *model.Player) *profil
 	r.Profile.Bio = u.Bio
 	r.Profile.Image = u.Image
 	r.Profile.Following, _ = us.IsFollower(u.ID, playerID)
+	r.Profile.FollowersCount = u.FollowersCount
 	return r
 }
 
@@ -57,6 +110,7 @@ type itemResponse struct {
 	UpdatedAt      time.Time `json:"updatedAt"`
 	Favorited      bool      `json:"favorited"`
 	FavoritesCount int       `json:"favoritesCount"`
+	CommentsCount  int       `json:"commentsCount"`
 	Author         struct {
 		Username  string  `json:"username"`
 		Bio       *string `json:"bio"`
@@ -74,7 +128,48 @@ type itemListResponse struct {
 	ItemsCount int             `json:"itemsCount"`
 }
 
-func newItemResponse(c echo.Context, a *model.Item) *singleItemResponse {
+// itemMeta holds the per-viewer data of a page of items, looked up in
+// batches rather than once per item.
+type itemMeta struct {
+	favorited map[uint]bool
+	following map[uint]bool
+}
+
+func newItemMeta(as item.Store, us player.Store, playerID uint, items []model.Item) (*itemMeta, error) {
+	itemIDs := make([]uint, 0, len(items))
+	authorIDs := make([]uint, 0, len(items))
+	for _, a := range items {
+		itemIDs = append(itemIDs, a.ID)
+		authorIDs = append(authorIDs, a.AuthorID)
+	}
+	var (
+		m   itemMeta
+		err error
+	)
+	if m.favorited, err = as.FavoritedBy(playerID, itemIDs); err != nil {
+		return nil, err
+	}
+	if m.following, err = us.FollowingSet(playerID, authorIDs); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func newItemResponse(a *model.Item, m *itemMeta) *singleItemResponse {
+	return &singleItemResponse{newItem(a, m)}
+}
+
+func newItemListResponse(items []model.Item, count int, m *itemMeta) *itemListResponse {
+	r := new(itemListResponse)
+	r.Items = make([]*itemResponse, 0)
+	for i := range items {
+		r.Items = append(r.Items, newItem(&items[i], m))
+	}
+	r.ItemsCount = count
+	return r
+}
+
+func newItem(a *model.Item, m *itemMeta) *itemResponse {
 	ar := new(itemResponse)
 	ar.TagList = make([]string, 0)
 	ar.Slug = a.Slug
@@ -86,48 +181,32 @@ func newItemResponse(c echo.Context, a *model.Item) *singleItemResponse {
 	for _, t := range a.Tags {
 		ar.TagList = append(ar.TagList, t.Tag)
 	}
-	for _, u := range a.Favorites {
-		if u.ID == playerIDFromToken(c) {
-			ar.Favorited = true
-		}
-	}
-	ar.FavoritesCount = len(a.Favorites)
+	ar.Favorited = m.favorited[a.ID]
+	ar.FavoritesCount = a.FavoritesCount
+	ar.CommentsCount = a.CommentsCount
 	ar.Author.Username = a.Author.Username
 	ar.Author.Image = a.Author.Image
 	ar.Author.Bio = a.Author.Bio
-	ar.Author.Following = a.Author.FollowedBy(playerIDFromToken(c))
-	return &singleItemResponse{ar}
+	ar.Author.Following = m.following[a.AuthorID]
+	return ar
 }
 
-func newItemListResponse(us player.Store, playerID uint, items []model.Item, count int) *itemListResponse {
-	r := new(itemListResponse)
-	r.Items = make([]*itemResponse, 0)
-	for _, a := range items {
-		ar := new(itemResponse)
-		ar.TagList = make([]string, 0)
-		ar.Slug = a.Slug
-		ar.Title = a.Title
-		ar.Description = a.Description
-		ar.Body = a.Body
-		ar.CreatedAt = a.CreatedAt
-		ar.UpdatedAt = a.UpdatedAt
-		for _, t := range a.Tags {
-			ar.TagList = append(ar.TagList, t.Tag)
-		}
-		for _, u := range a.Favorites {
-			if u.ID == playerID {
-				ar.Favorited = true
-			}
-		}
-		ar.FavoritesCount = len(a.Favorites)
-		ar.Author.Username = a.Author.Username
-		ar.Author.Image = a.Author.Image
-		ar.Author.Bio = a.Author.Bio
-		ar.Author.Following, _ = us.IsFollower(a.AuthorID, playerID)
-		r.Items = append(r.Items, ar)
+// itemColumns head the CSV form of item lists. Tags are joined by commas
+// and the author is named by username.
+var itemColumns = []string{"slug", "title", "description", "body", "tagList", "createdAt", "updatedAt",
+	"favorited", "favoritesCount", "commentsCount", "author"}
+
+func (r *itemListResponse) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Items))
+	for _, a := range r.Items {
+		rows = append(rows, []string{
+			a.Slug, a.Title, a.Description, a.Body, strings.Join(a.TagList, ","),
+			a.CreatedAt.Format(time.RFC3339Nano), a.UpdatedAt.Format(time.RFC3339Nano),
+			strconv.FormatBool(a.Favorited), strconv.Itoa(a.FavoritesCount), strconv.Itoa(a.CommentsCount),
+			a.Author.Username,
+		})
 	}
-	r.ItemsCount = count
-	return r
+	return itemColumns, rows
 }
 
 type commentResponse struct {
@@ -187,6 +266,14 @@ type tagListResponse struct {
 	Tags []string `json:"tags"`
 }
 
+func (r *tagListResponse) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Tags))
+	for _, t := range r.Tags {
+		rows = append(rows, []string{t})
+	}
+	return []string{"tag"}, rows
+}
+
 func newTagListResponse(tags []model.Tag) *tagListResponse {
 	r := new(tagListResponse)
 	for _, t := range tags {
@@ -194,3 +281,73 @@ func newTagListResponse(tags []model.Tag) *tagListResponse {
 	}
 	return r
 }
+
+type apiKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type singleAPIKeyResponse struct {
+	APIKey *apiKeyResponse `json:"apiKey"`
+}
+
+type apiKeyListResponse struct {
+	APIKeys []*apiKeyResponse `json:"apiKeys"`
+}
+
+// newAPIKeyResponse includes the plain key, which is only known right after
+// creation; pass an empty key everywhere else.
+func newAPIKeyResponse(k *model.APIKey, key string) *singleAPIKeyResponse {
+	return &singleAPIKeyResponse{newAPIKey(k, key)}
+}
+
+func newAPIKeyListResponse(keys []model.APIKey) *apiKeyListResponse {
+	r := new(apiKeyListResponse)
+	r.APIKeys = make([]*apiKeyResponse, 0)
+	for i := range keys {
+		r.APIKeys = append(r.APIKeys, newAPIKey(&keys[i], ""))
+	}
+	return r
+}
+
+func newAPIKey(k *model.APIKey, key string) *apiKeyResponse {
+	r := new(apiKeyResponse)
+	r.ID = k.ID
+	r.Name = k.Name
+	r.Prefix = k.Prefix
+	r.Scopes = k.ScopeList()
+	r.Key = key
+	r.CreatedAt = k.CreatedAt
+	r.LastUsedAt = k.LastUsedAt
+	return r
+}
+
+type cacheStatsResponse struct {
+	Cache struct {
+		Hits    uint64  `json:"hits"`
+		Misses  uint64  `json:"misses"`
+		HitRate float64 `json:"hitRate"`
+	} `json:"cache"`
+}
+
+func newCacheStatsResponse(s *cache.Stats) *cacheStatsResponse {
+	r := new(cacheStatsResponse)
+	r.Cache.Hits = s.Hits()
+	r.Cache.Misses = s.Misses()
+	r.Cache.HitRate = s.HitRate()
+	return r
+}
+
+// resultResponse acknowledges actions that have nothing else to return.
+type resultResponse struct {
+	Result string `json:"result"`
+}
+
+func newResultResponse() *resultResponse {
+	return &resultResponse{Result: "ok"}
+}
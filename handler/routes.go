@@ -1,49 +1,146 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
+	"golang-starter-pack/model"
 	"golang-starter-pack/router/middleware"
 	"golang-starter-pack/utils"
 )
 
+// Rate limit policies, from strictest to most lenient.
+var (
+	authRateLimit   = middleware.RateLimitPolicy{Rate: 5.0 / 60, Burst: 5}
+	createRateLimit = middleware.RateLimitPolicy{Rate: 1.0 / 60, Burst: 10}
+	writeRateLimit  = middleware.RateLimitPolicy{Rate: 1, Burst: 20}
+	readRateLimit   = middleware.RateLimitPolicy{Rate: 20, Burst: 100}
+)
+
+func (h *Handler) rateLimit(name string, p middleware.RateLimitPolicy) echo.MiddlewareFunc {
+	return middleware.RateLimitWithConfig(middleware.RateLimitConfig{
+		Name:   name,
+		Policy: p,
+		Store:  h.rateLimits,
+	})
+}
+
+// takeRateLimit charges a request to a policy's bucket from inside a
+// handler, for routes such as /graphql whose cost depends on the body.
+func (h *Handler) takeRateLimit(c echo.Context, name string, p middleware.RateLimitPolicy) error {
+	res, err := h.rateLimits.Take(name+":"+middleware.RateLimitKey(c), p, time.Now())
+	if err != nil {
+		h.log(c).Warn("rate limit store failed", "limit", name, "err", err)
+		return nil
+	}
+	if !res.Allowed {
+		return utils.ErrRateLimited()
+	}
+	return nil
+}
+
+// Register adds the API to a group mounted at /api. Route /api/vN/...
+// to it with middleware.StripAPIVersion to serve versions by path.
 func (h *Handler) Register(v1 *echo.Group) {
-	jwtMiddleware := middleware.JWT(utils.JWTSecret)
-	guestPlayers := v1.Group("/players")
+	v1.Use(middleware.Render(), h.apiVersioning())
+	authConfig := middleware.JWTConfig{
+		KeySet:   utils.DefaultKeySet,
+		Issuer:   utils.JWTIssuer,
+		Audience: utils.JWTAudience,
+		Extractors: []middleware.TokenExtractor{
+			middleware.FromHeader("Authorization", "Token"),
+			middleware.FromHeader("Authorization", "Bearer"),
+			middleware.FromCookie(middleware.SessionCookie),
+			middleware.FromQuery("access_token"),
+		},
+		APIKeyValidator: h.validateAPIKey,
+	}
+	jwtMiddleware := middleware.JWTWithConfig(authConfig)
+	authLimit := h.rateLimit("auth", authRateLimit)
+	writeLimit := h.rateLimit("write", writeRateLimit)
+	readLimit := h.rateLimit("read", readRateLimit)
+	// methodLimit applies the read policy to GETs and the write policy to
+	// everything else.
+	methodLimit := func(next echo.HandlerFunc) echo.HandlerFunc {
+		read, write := readLimit(next), writeLimit(next)
+		return func(c echo.Context) error {
+			if c.Request().Method == echo.GET {
+				return read(c)
+			}
+			return write(c)
+		}
+	}
+
+	guestPlayers := v1.Group("/players", authLimit)
 	guestPlayers.POST("", h.SignUp)
 	guestPlayers.POST("/login", h.Login)
+	guestPlayers.POST("/login/2fa", h.LoginTwoFactor)
 
-	player := v1.Group("/player", jwtMiddleware)
+	player := v1.Group("/player", jwtMiddleware, methodLimit)
 	player.GET("", h.CurrentPlayer)
-	player.PUT("", h.UpdatePlayer)
+	player.POST("/logout", h.Logout)
+	player.PUT("", h.UpdatePlayer, middleware.RequireScope(model.ScopeProfileWrite))
+	player.PATCH("", h.PatchPlayer, middleware.RequireScope(model.ScopeProfileWrite))
+	player.POST("/2fa", h.EnrollTwoFactor, middleware.RequireSession)
+	player.POST("/2fa/verify", h.VerifyTwoFactor, middleware.RequireSession)
+	player.DELETE("/2fa", h.DisableTwoFactor, middleware.RequireSession)
+	player.POST("/keys", h.CreateAPIKey, middleware.RequireSession)
+	player.GET("/keys", h.APIKeys, middleware.RequireSession)
+	player.DELETE("/keys/:id", h.RevokeAPIKey, middleware.RequireSession)
 
-	profiles := v1.Group("/profiles", jwtMiddleware)
+	profiles := v1.Group("/profiles", jwtMiddleware, methodLimit)
 	profiles.GET("/:username", h.GetProfile)
-	profiles.POST("/:username/follow", h.Follow)
-	profiles.DELETE("/:username/follow", h.Unfollow)
-
-	items := v1.Group("/items", middleware.JWTWithConfig(
-		middleware.JWTConfig{
-			Skipper: func(c echo.Context) bool {
-				if c.Request().Method == "GET" && c.Path() != "/api/items/feed" {
-					return true
-				}
-				return false
-			},
-			SigningKey: utils.JWTSecret,
-		},
-	))
-	items.POST("", h.CreateItem)
-	items.GET("/feed", h.Feed)
-	items.PUT("/:slug", h.UpdateItem)
-	items.DELETE("/:slug", h.DeleteItem)
-	items.POST("/:slug/comments", h.AddComment)
-	items.DELETE("/:slug/comments/:id", h.DeleteComment)
-	items.POST("/:slug/favorite", h.Favorite)
-	items.DELETE("/:slug/favorite", h.Unfavorite)
-	items.GET("", h.Items)
-	items.GET("/:slug", h.GetItem)
-	items.GET("/:slug/comments", h.GetComments)
-
-	tags := v1.Group("/tags")
+	profiles.POST("/:username/follow", h.Follow, middleware.RequireScope(model.ScopeProfileWrite))
+	profiles.DELETE("/:username/follow", h.Unfollow, middleware.RequireScope(model.ScopeProfileWrite))
+
+	itemsAuth := authConfig
+	itemsAuth.Skipper = func(c echo.Context) bool {
+		if c.Request().Method == "GET" && c.Path() != "/api/items/feed" {
+			return true
+		}
+		return false
+	}
+	items := v1.Group("/items", middleware.JWTWithConfig(itemsAuth), methodLimit)
+	itemsRead := middleware.RequireScope(model.ScopeItemsRead)
+	itemsWrite := middleware.RequireScope(model.ScopeItemsWrite)
+	commentsWrite := middleware.RequireScope(model.ScopeCommentsWrite)
+	items.POST("", h.CreateItem, itemsWrite, h.rateLimit("create", createRateLimit))
+	items.GET("/feed", h.Feed, itemsRead)
+	items.PUT("/:slug", h.UpdateItem, itemsWrite)
+	items.PATCH("/:slug", h.PatchItem, itemsWrite)
+	items.DELETE("/:slug", h.DeleteItem, itemsWrite)
+	items.POST("/:slug/comments", h.AddComment, commentsWrite)
+	items.DELETE("/:slug/comments/:id", h.DeleteComment, commentsWrite)
+	items.POST("/:slug/favorite", h.Favorite, itemsWrite)
+	items.DELETE("/:slug/favorite", h.Unfavorite, itemsWrite)
+	items.GET("", h.Items, itemsRead)
+	items.GET("/:slug", h.GetItem, itemsRead)
+	items.GET("/:slug/comments", h.GetComments, itemsRead)
+
+	tags := v1.Group("/tags", readLimit)
 	tags.GET("", h.Tags)
+
+	// Feeds are public, so they sit outside the groups requiring a token.
+	for _, ext := range []string{".rss", ".atom"} {
+		v1.GET("/items"+ext, h.ItemsFeed, readLimit)
+		v1.GET("/profiles/:username/items"+ext, h.AuthorFeed, readLimit)
+		v1.GET("/tags/:tag/items"+ext, h.TagFeed, readLimit)
+	}
+
+	admin := v1.Group("/admin", jwtMiddleware, middleware.RequireSession, h.adminOnly)
+	admin.POST("/players/:username/unlock", h.UnlockPlayer)
+	admin.GET("/cache", h.CacheStats)
+
+	// Anyone may query; mutations check for a player themselves.
+	graphQLAuth := authConfig
+	graphQLAuth.Skipper = func(c echo.Context) bool {
+		return true
+	}
+	graphQL := v1.Group("/graphql", middleware.JWTWithConfig(graphQLAuth), readLimit)
+	graphQL.GET("", h.GraphQL)
+	graphQL.POST("", h.GraphQL)
+	graphQL.GET("/schema", h.GraphQLSchema)
+
+	v1.GET("/openapi.json", h.OpenAPISpec)
+	v1.GET("/docs", h.SwaggerUI)
 }
@@ -0,0 +1,594 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/item"
+	"golang-starter-pack/pb"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // accept compressed requests
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// rpcMetadata lists the metadata passed on to the REST route of a call.
+var rpcMetadata = []string{
+	echo.HeaderAuthorization,
+	"Accept-Language",
+	router.RequestIDHeader,
+	router.TraceparentHeader,
+}
+
+// rpcRoute maps a gRPC method onto the REST route serving it. The method
+// and path are read from the method's google.api.http option.
+type rpcRoute struct {
+	method, path string
+	// rest returns the path parameters, query and JSON body of the call.
+	rest  func(req proto.Message) restCall
+	reply func(body []byte) (proto.Message, error)
+}
+
+type restCall struct {
+	params map[string]string
+	query  url.Values
+	body   interface{}
+}
+
+func init() {
+	services := pb.File_pb_starter_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			m := methods.Get(j)
+			route := rpcRoutes["/"+string(m.Parent().FullName())+"/"+string(m.Name())]
+			rule, _ := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+			if route == nil || rule == nil {
+				continue
+			}
+			switch p := rule.Pattern.(type) {
+			case *annotations.HttpRule_Get:
+				route.method, route.path = echo.GET, p.Get
+			case *annotations.HttpRule_Post:
+				route.method, route.path = echo.POST, p.Post
+			case *annotations.HttpRule_Put:
+				route.method, route.path = echo.PUT, p.Put
+			case *annotations.HttpRule_Patch:
+				route.method, route.path = echo.PATCH, p.Patch
+			case *annotations.HttpRule_Delete:
+				route.method, route.path = echo.DELETE, p.Delete
+			}
+		}
+	}
+}
+
+// NewRPCServer serves the gRPC API by calling api, the Echo instance the
+// REST routes are registered on, so that the two cannot drift apart. It
+// also serves reflection and the standard health service, which reports
+// healthServer's statuses; shut that down when draining.
+func NewRPCServer(api http.Handler, healthServer *health.Server, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	pb.RegisterPlayersServer(s, &playersRPC{api: api})
+	pb.RegisterItemsServer(s, &itemsRPC{api: api})
+	pb.RegisterCommentsServer(s, &commentsRPC{api: api})
+	pb.RegisterTagsServer(s, &tagsRPC{api: api})
+	for name := range s.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, healthServer)
+	reflection.Register(s)
+	return s
+}
+
+// callRPC serves the method named by its full name through its REST route.
+func callRPC[T proto.Message](ctx context.Context, api http.Handler, name string, req proto.Message) (T, error) {
+	var zero T
+	route := rpcRoutes[name]
+	if route == nil || route.path == "" {
+		return zero, status.Errorf(codes.Unimplemented, "method %s not implemented", name)
+	}
+	reply, err := route.call(ctx, api, req)
+	if err != nil {
+		return zero, err
+	}
+	return reply.(T), nil
+}
+
+func (route *rpcRoute) call(ctx context.Context, api http.Handler, req proto.Message) (proto.Message, error) {
+	call := route.rest(req)
+	path := route.path
+	for name, v := range call.params {
+		path = strings.Replace(path, "{"+name+"}", url.PathEscape(v), 1)
+	}
+	if len(call.query) > 0 {
+		path += "?" + call.query.Encode()
+	}
+	var body io.Reader
+	if call.body != nil {
+		b, err := json.Marshal(call.body)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		body = bytes.NewReader(b)
+	}
+	r, err := http.NewRequest(route.method, path, body)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, k := range rpcMetadata {
+		if v := md.Get(k); len(v) > 0 {
+			r.Header.Set(k, v[0])
+		}
+	}
+	if route.method == echo.PATCH {
+		// Updates send only the fields to change.
+		r.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	} else if body != nil {
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+	w := &responseBuffer{header: make(http.Header), status: http.StatusOK}
+	api.ServeHTTP(w, r.WithContext(ctx))
+	if w.status >= http.StatusBadRequest {
+		return nil, restStatus(w.status, w.body.Bytes())
+	}
+	return route.reply(w.body.Bytes())
+}
+
+// playersRPC, itemsRPC, commentsRPC and tagsRPC implement the generated
+// service interfaces by handing each call to its REST route.
+
+type playersRPC struct {
+	pb.UnimplementedPlayersServer
+	api http.Handler
+}
+
+func (s *playersRPC) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	return callRPC[*pb.LoginResponse](ctx, s.api, pb.Players_Login_FullMethodName, req)
+}
+
+func (s *playersRPC) GetCurrentPlayer(ctx context.Context, req *pb.GetCurrentPlayerRequest) (*pb.Player, error) {
+	return callRPC[*pb.Player](ctx, s.api, pb.Players_GetCurrentPlayer_FullMethodName, req)
+}
+
+func (s *playersRPC) UpdatePlayer(ctx context.Context, req *pb.UpdatePlayerRequest) (*pb.Player, error) {
+	return callRPC[*pb.Player](ctx, s.api, pb.Players_UpdatePlayer_FullMethodName, req)
+}
+
+func (s *playersRPC) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.Profile, error) {
+	return callRPC[*pb.Profile](ctx, s.api, pb.Players_GetProfile_FullMethodName, req)
+}
+
+func (s *playersRPC) FollowPlayer(ctx context.Context, req *pb.FollowPlayerRequest) (*pb.Profile, error) {
+	return callRPC[*pb.Profile](ctx, s.api, pb.Players_FollowPlayer_FullMethodName, req)
+}
+
+func (s *playersRPC) UnfollowPlayer(ctx context.Context, req *pb.FollowPlayerRequest) (*pb.Profile, error) {
+	return callRPC[*pb.Profile](ctx, s.api, pb.Players_UnfollowPlayer_FullMethodName, req)
+}
+
+type itemsRPC struct {
+	pb.UnimplementedItemsServer
+	api http.Handler
+}
+
+func (s *itemsRPC) ListItems(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListItemsResponse, error) {
+	return callRPC[*pb.ListItemsResponse](ctx, s.api, pb.Items_ListItems_FullMethodName, req)
+}
+
+func (s *itemsRPC) GetFeed(ctx context.Context, req *pb.GetFeedRequest) (*pb.ListItemsResponse, error) {
+	return callRPC[*pb.ListItemsResponse](ctx, s.api, pb.Items_GetFeed_FullMethodName, req)
+}
+
+func (s *itemsRPC) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.Item, error) {
+	return callRPC[*pb.Item](ctx, s.api, pb.Items_GetItem_FullMethodName, req)
+}
+
+func (s *itemsRPC) CreateItem(ctx context.Context, req *pb.CreateItemRequest) (*pb.Item, error) {
+	return callRPC[*pb.Item](ctx, s.api, pb.Items_CreateItem_FullMethodName, req)
+}
+
+func (s *itemsRPC) UpdateItem(ctx context.Context, req *pb.UpdateItemRequest) (*pb.Item, error) {
+	return callRPC[*pb.Item](ctx, s.api, pb.Items_UpdateItem_FullMethodName, req)
+}
+
+func (s *itemsRPC) DeleteItem(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	return callRPC[*pb.DeleteItemResponse](ctx, s.api, pb.Items_DeleteItem_FullMethodName, req)
+}
+
+func (s *itemsRPC) FavoriteItem(ctx context.Context, req *pb.FavoriteItemRequest) (*pb.Item, error) {
+	return callRPC[*pb.Item](ctx, s.api, pb.Items_FavoriteItem_FullMethodName, req)
+}
+
+func (s *itemsRPC) UnfavoriteItem(ctx context.Context, req *pb.FavoriteItemRequest) (*pb.Item, error) {
+	return callRPC[*pb.Item](ctx, s.api, pb.Items_UnfavoriteItem_FullMethodName, req)
+}
+
+type commentsRPC struct {
+	pb.UnimplementedCommentsServer
+	api http.Handler
+}
+
+func (s *commentsRPC) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	return callRPC[*pb.ListCommentsResponse](ctx, s.api, pb.Comments_ListComments_FullMethodName, req)
+}
+
+func (s *commentsRPC) AddComment(ctx context.Context, req *pb.AddCommentRequest) (*pb.Comment, error) {
+	return callRPC[*pb.Comment](ctx, s.api, pb.Comments_AddComment_FullMethodName, req)
+}
+
+func (s *commentsRPC) DeleteComment(ctx context.Context, req *pb.DeleteCommentRequest) (*pb.DeleteCommentResponse, error) {
+	return callRPC[*pb.DeleteCommentResponse](ctx, s.api, pb.Comments_DeleteComment_FullMethodName, req)
+}
+
+type tagsRPC struct {
+	pb.UnimplementedTagsServer
+	api http.Handler
+}
+
+func (s *tagsRPC) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	return callRPC[*pb.ListTagsResponse](ctx, s.api, pb.Tags_ListTags_FullMethodName, req)
+}
+
+// responseBuffer records the response of a REST route.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseBuffer) Header() http.Header {
+	return w.header
+}
+
+func (w *responseBuffer) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *responseBuffer) WriteHeader(status int) {
+	w.status = status
+}
+
+// restStatus turns an error response into a status, with the details of
+// validation errors in its message.
+func restStatus(httpStatus int, body []byte) error {
+	var e utils.Error
+	if err := json.Unmarshal(body, &e); err != nil || e.Errors.Message == "" {
+		return grpcStatus(httpStatus, http.StatusText(httpStatus))
+	}
+	msg := e.Errors.Message
+	if len(e.Errors.Fields) > 0 {
+		fields := make([]string, 0, len(e.Errors.Fields))
+		for name, f := range e.Errors.Fields {
+			fields = append(fields, name+": "+f.Message)
+		}
+		sort.Strings(fields)
+		msg += " (" + strings.Join(fields, "; ") + ")"
+	}
+	return grpcStatus(httpStatus, msg)
+}
+
+// grpcStatus maps an HTTP status to the gRPC code with the same meaning,
+// the inverse of the mapping grpc-gateway uses.
+func grpcStatus(httpStatus int, msg string) error {
+	code := codes.Internal
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusPreconditionFailed, http.StatusLocked:
+		code = codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		code = codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	case http.StatusGatewayTimeout:
+		code = codes.DeadlineExceeded
+	default:
+		if httpStatus < http.StatusInternalServerError {
+			code = codes.FailedPrecondition
+		}
+	}
+	return status.Error(code, msg)
+}
+
+func decodeReply(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return status.Errorf(codes.Internal, "decoding reply: %v", err)
+	}
+	return nil
+}
+
+var rpcOrders = map[pb.ItemOrder]item.Order{
+	pb.ItemOrder_ITEM_ORDER_RECENT:    item.OrderRecent,
+	pb.ItemOrder_ITEM_ORDER_FAVORITES: item.OrderFavorites,
+	pb.ItemOrder_ITEM_ORDER_COMMENTS:  item.OrderComments,
+}
+
+func pageQuery(limit, offset int32, order pb.ItemOrder) url.Values {
+	q := url.Values{}
+	if limit != 0 {
+		q.Set("limit", strconv.Itoa(int(limit)))
+	}
+	if offset != 0 {
+		q.Set("offset", strconv.Itoa(int(offset)))
+	}
+	if order != pb.ItemOrder_ITEM_ORDER_RECENT {
+		q.Set("sort", string(rpcOrders[order]))
+	}
+	return q
+}
+
+// setIf adds a field to a PATCH body unless it is empty, so that empty
+// fields of a request keep their values.
+func setIf(m map[string]interface{}, name, v string) {
+	if v != "" {
+		m[name] = v
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func newPlayerMessage(r *playerResponse) *pb.Player {
+	return &pb.Player{Username: r.Player.Username, Email: r.Player.Email,
+		Bio: deref(r.Player.Bio), Image: deref(r.Player.Image), Token: r.Player.Token}
+}
+
+func playerMessage(body []byte) (proto.Message, error) {
+	var r playerResponse
+	if err := decodeReply(body, &r); err != nil {
+		return nil, err
+	}
+	return newPlayerMessage(&r), nil
+}
+
+func profileMessage(body []byte) (proto.Message, error) {
+	var r profileResponse
+	if err := decodeReply(body, &r); err != nil {
+		return nil, err
+	}
+	return &pb.Profile{Username: r.Profile.Username, Bio: deref(r.Profile.Bio), Image: deref(r.Profile.Image),
+		Following: r.Profile.Following, FollowersCount: int32(r.Profile.FollowersCount)}, nil
+}
+
+func newItemMessage(a *itemResponse) *pb.Item {
+	return &pb.Item{
+		Slug:           a.Slug,
+		Title:          a.Title,
+		Description:    a.Description,
+		Body:           a.Body,
+		TagList:        a.TagList,
+		CreatedAt:      timestamppb.New(a.CreatedAt),
+		UpdatedAt:      timestamppb.New(a.UpdatedAt),
+		Favorited:      a.Favorited,
+		FavoritesCount: int32(a.FavoritesCount),
+		CommentsCount:  int32(a.CommentsCount),
+		Author: &pb.Profile{Username: a.Author.Username, Bio: deref(a.Author.Bio), Image: deref(a.Author.Image),
+			Following: a.Author.Following},
+	}
+}
+
+func itemMessage(body []byte) (proto.Message, error) {
+	var r singleItemResponse
+	if err := decodeReply(body, &r); err != nil {
+		return nil, err
+	}
+	return newItemMessage(r.Item), nil
+}
+
+func itemListMessage(body []byte) (proto.Message, error) {
+	var r itemListResponse
+	if err := decodeReply(body, &r); err != nil {
+		return nil, err
+	}
+	m := &pb.ListItemsResponse{ItemsCount: int32(r.ItemsCount)}
+	for _, a := range r.Items {
+		m.Items = append(m.Items, newItemMessage(a))
+	}
+	return m, nil
+}
+
+func newCommentMessage(cm *commentResponse) *pb.Comment {
+	return &pb.Comment{
+		Id:        uint64(cm.ID),
+		Body:      cm.Body,
+		CreatedAt: timestamppb.New(cm.CreatedAt),
+		UpdatedAt: timestamppb.New(cm.UpdatedAt),
+		Author: &pb.Profile{Username: cm.Author.Username, Bio: deref(cm.Author.Bio), Image: deref(cm.Author.Image),
+			Following: cm.Author.Following},
+	}
+}
+
+var rpcRoutes = map[string]*rpcRoute{
+	pb.Players_Login_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.LoginRequest)
+			var b playerLoginRequest
+			b.Player.Email, b.Player.Password = m.Email, m.Password
+			return restCall{body: b}
+		},
+		reply: func(body []byte) (proto.Message, error) {
+			var r struct {
+				playerResponse
+				loginChallengeResponse
+			}
+			if err := decodeReply(body, &r); err != nil {
+				return nil, err
+			}
+			if r.Challenge.Token != "" {
+				return &pb.LoginResponse{ChallengeToken: r.Challenge.Token, ChallengeExpiresIn: int32(r.Challenge.ExpiresIn)}, nil
+			}
+			return &pb.LoginResponse{Player: newPlayerMessage(&r.playerResponse)}, nil
+		}},
+	pb.Players_GetCurrentPlayer_FullMethodName: {
+		rest:  func(req proto.Message) restCall { return restCall{} },
+		reply: playerMessage},
+	pb.Players_UpdatePlayer_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.UpdatePlayerRequest)
+			p := make(map[string]interface{})
+			setIf(p, "username", m.Username)
+			setIf(p, "email", m.Email)
+			setIf(p, "password", m.Password)
+			setIf(p, "bio", m.Bio)
+			setIf(p, "image", m.Image)
+			return restCall{body: map[string]interface{}{"player": p}}
+		},
+		reply: playerMessage},
+	pb.Players_GetProfile_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"username": req.(*pb.GetProfileRequest).Username}}
+		},
+		reply: profileMessage},
+	pb.Players_FollowPlayer_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"username": req.(*pb.FollowPlayerRequest).Username}}
+		},
+		reply: profileMessage},
+	pb.Players_UnfollowPlayer_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"username": req.(*pb.FollowPlayerRequest).Username}}
+		},
+		reply: profileMessage},
+
+	pb.Items_ListItems_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.ListItemsRequest)
+			q := pageQuery(m.Limit, m.Offset, m.Sort)
+			for name, v := range map[string]string{"tag": m.Tag, "author": m.Author, "favorited": m.Favorited} {
+				if v != "" {
+					q.Set(name, v)
+				}
+			}
+			return restCall{query: q}
+		},
+		reply: itemListMessage},
+	pb.Items_GetFeed_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.GetFeedRequest)
+			return restCall{query: pageQuery(m.Limit, m.Offset, m.Sort)}
+		},
+		reply: itemListMessage},
+	pb.Items_GetItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"slug": req.(*pb.GetItemRequest).Slug}}
+		},
+		reply: itemMessage},
+	pb.Items_CreateItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.CreateItemRequest)
+			var b itemCreateRequest
+			b.Items.Title, b.Items.Description, b.Items.Body, b.Items.Tags = m.Title, m.Description, m.Body, m.TagList
+			return restCall{body: b}
+		},
+		reply: itemMessage},
+	pb.Items_UpdateItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.UpdateItemRequest)
+			a := make(map[string]interface{})
+			setIf(a, "title", m.Title)
+			setIf(a, "description", m.Description)
+			setIf(a, "body", m.Body)
+			if len(m.TagList) > 0 {
+				a["tagList"] = m.TagList
+			}
+			return restCall{params: map[string]string{"slug": m.Slug}, body: map[string]interface{}{"item": a}}
+		},
+		reply: itemMessage},
+	pb.Items_DeleteItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"slug": req.(*pb.DeleteItemRequest).Slug}}
+		},
+		reply: func(body []byte) (proto.Message, error) { return new(pb.DeleteItemResponse), nil }},
+	pb.Items_FavoriteItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"slug": req.(*pb.FavoriteItemRequest).Slug}}
+		},
+		reply: itemMessage},
+	pb.Items_UnfavoriteItem_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"slug": req.(*pb.FavoriteItemRequest).Slug}}
+		},
+		reply: itemMessage},
+
+	pb.Comments_ListComments_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			return restCall{params: map[string]string{"slug": req.(*pb.ListCommentsRequest).Slug}}
+		},
+		reply: func(body []byte) (proto.Message, error) {
+			var r commentListResponse
+			if err := decodeReply(body, &r); err != nil {
+				return nil, err
+			}
+			m := new(pb.ListCommentsResponse)
+			for i := range r.Comments {
+				m.Comments = append(m.Comments, newCommentMessage(&r.Comments[i]))
+			}
+			return m, nil
+		}},
+	pb.Comments_AddComment_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.AddCommentRequest)
+			var b createCommentRequest
+			b.Comment.Body = m.Body
+			return restCall{params: map[string]string{"slug": m.Slug}, body: b}
+		},
+		reply: func(body []byte) (proto.Message, error) {
+			var r singleCommentResponse
+			if err := decodeReply(body, &r); err != nil {
+				return nil, err
+			}
+			return newCommentMessage(r.Comment), nil
+		}},
+	pb.Comments_DeleteComment_FullMethodName: {
+		rest: func(req proto.Message) restCall {
+			m := req.(*pb.DeleteCommentRequest)
+			return restCall{params: map[string]string{"slug": m.Slug, "id": strconv.FormatUint(m.Id, 10)}}
+		},
+		reply: func(body []byte) (proto.Message, error) { return new(pb.DeleteCommentResponse), nil }},
+
+	pb.Tags_ListTags_FullMethodName: {
+		rest: func(req proto.Message) restCall { return restCall{} },
+		reply: func(body []byte) (proto.Message, error) {
+			var r tagListResponse
+			if err := decodeReply(body, &r); err != nil {
+				return nil, err
+			}
+			return &pb.ListTagsResponse{Tags: r.Tags}, nil
+		}},
+}
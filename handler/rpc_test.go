@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/pb"
+	"golang-starter-pack/router"
+	"golang-starter-pack/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newRPCTestServer serves the gRPC API over an in-memory listener and
+// returns a client connection to it.
+func newRPCTestServer() (*echo.Echo, *grpc.ClientConn, *health.Server, func()) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+	hs := health.NewServer()
+	s := NewRPCServer(e, hs)
+	l := bufconn.Listen(1 << 20)
+	go s.Serve(l)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	return e, conn, hs, func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func withToken(ctx context.Context, id uint) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", authHeader(utils.GenerateJWT(id)))
+}
+
+func TestRPCMatchesREST(t *testing.T) {
+	tearDown()
+	setup()
+	e, conn, _, done := newRPCTestServer()
+	defer done()
+	ctx := context.Background()
+	items := pb.NewItemsClient(conn)
+
+	got, err := items.GetItem(withToken(ctx, 1), &pb.GetItemRequest{Slug: "item2-slug"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	req := httptest.NewRequest(echo.GET, "/api/items/item2-slug", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var want singleItemResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &want))
+	assert.Equal(t, newItemMessage(want.Item).String(), got.String())
+	assert.True(t, got.Favorited)
+	assert.True(t, got.Author.Following)
+	assert.Equal(t, want.Item.CreatedAt.UTC(), got.CreatedAt.AsTime())
+
+	list, err := items.ListItems(ctx, &pb.ListItemsRequest{Tag: "tag2"})
+	if assert.NoError(t, err) && assert.Len(t, list.Items, 1) {
+		assert.Equal(t, "item1-slug", list.Items[0].Slug)
+		assert.Equal(t, []string{"tag1", "tag2"}, list.Items[0].TagList)
+		assert.Equal(t, int32(1), list.ItemsCount)
+	}
+
+	// Compressed requests are accepted.
+	comments, err := pb.NewCommentsClient(conn).ListComments(ctx, &pb.ListCommentsRequest{Slug: "item1-slug"}, grpc.UseCompressor(gzip.Name))
+	if assert.NoError(t, err) && assert.Len(t, comments.Comments, 1) {
+		assert.Equal(t, "item1 comment1", comments.Comments[0].Body)
+		assert.Equal(t, "player1", comments.Comments[0].Author.Username)
+	}
+}
+
+func TestRPCAuthAndErrors(t *testing.T) {
+	tearDown()
+	setup()
+	_, conn, _, done := newRPCTestServer()
+	defer done()
+	ctx := context.Background()
+	items := pb.NewItemsClient(conn)
+
+	create := &pb.CreateItemRequest{Title: "rpc item", Description: "d", Body: "b", TagList: []string{"rpc"}}
+	_, err := items.CreateItem(ctx, create)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	login, err := pb.NewPlayersClient(conn).Login(ctx, &pb.LoginRequest{Email: "player1@realworld.io", Password: "secret"})
+	if !assert.NoError(t, err) || !assert.NotNil(t, login.Player) {
+		return
+	}
+	authed := metadata.AppendToOutgoingContext(ctx, "authorization", "Token "+login.Player.Token)
+	if a, err := items.CreateItem(authed, create); assert.NoError(t, err) {
+		assert.Equal(t, "rpc-item", a.Slug)
+		assert.Equal(t, "player1", a.Author.Username)
+	}
+
+	if updated, err := items.UpdateItem(authed, &pb.UpdateItemRequest{Slug: "rpc-item", Body: "new body"}); assert.NoError(t, err) {
+		assert.Equal(t, "new body", updated.Body)
+		assert.Equal(t, "rpc item", updated.Title)
+		assert.Equal(t, []string{"rpc"}, updated.TagList)
+	}
+
+	_, err = items.CreateItem(authed, &pb.CreateItemRequest{Title: "no body"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "body")
+
+	_, err = items.DeleteItem(withToken(ctx, 2), &pb.DeleteItemRequest{Slug: "rpc-item"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = items.DeleteItem(authed, &pb.DeleteItemRequest{Slug: "rpc-item"})
+	assert.NoError(t, err)
+
+	err = conn.Invoke(ctx, "/starter.v1.Items/Nope", &pb.ListTagsRequest{}, new(pb.ListTagsResponse))
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestRPCHealthAndReflection(t *testing.T) {
+	tearDown()
+	setup()
+	_, conn, hs, done := newRPCTestServer()
+	defer done()
+	ctx := context.Background()
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if !assert.NoError(t, err) {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return res.Status
+	}
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check("starter.v1.Items"))
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	res, err := stream.Recv()
+	if assert.NoError(t, err) {
+		var names []string
+		for _, s := range res.GetListServicesResponse().Service {
+			names = append(names, s.Name)
+		}
+		assert.Subset(t, names, []string{"starter.v1.Players", "starter.v1.Items", "starter.v1.Comments", "starter.v1.Tags", "grpc.health.v1.Health"})
+	}
+	assert.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	hs.Shutdown()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+}
+
+// TestRPCNoDrift checks that every method of starter.proto has a route
+// from its google.api.http option, and that every route exists.
+func TestRPCNoDrift(t *testing.T) {
+	e := router.New()
+	h.Register(e.Group("/api"))
+	routes := make(map[string]bool)
+	for _, r := range e.Routes() {
+		routes[r.Method+" "+routeParam.ReplaceAllString(r.Path, "{$1}")] = true
+	}
+	methods := 0
+	services := pb.File_pb_starter_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		s := services.Get(i)
+		for j := 0; j < s.Methods().Len(); j++ {
+			name := "/" + string(s.FullName()) + "/" + string(s.Methods().Get(j).Name())
+			methods++
+			route := rpcRoutes[name]
+			if !assert.NotNil(t, route, "%s has no route", name) || !assert.NotEmpty(t, route.path, "%s has no google.api.http option", name) {
+				continue
+			}
+			assert.True(t, routes[route.method+" "+route.path], "%s maps to %s %s, which is not routed", name, route.method, route.path)
+		}
+	}
+	assert.Len(t, rpcRoutes, methods, "rpcRoutes has routes for methods starter.proto lacks")
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"golang-starter-pack/db"
+	"golang-starter-pack/router"
+)
+
+func spanAttribute(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingCaseSuccess(t *testing.T) {
+	tearDown()
+	setup()
+	db.Trace(d)
+	exp := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	defer otel.SetTracerProvider(prev)
+	e := router.New()
+	h.Register(e.Group("/api"))
+
+	req := httptest.NewRequest(echo.GET, "/api/items/item1-slug", nil)
+	req.Header.Set(router.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", rec.Header().Get(router.TraceIDHeader))
+	assert.Contains(t, rec.Header().Get(router.TraceparentHeader), "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	byName := make(map[string]tracetest.SpanStub)
+	for _, s := range exp.GetSpans() {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.SpanContext.TraceID().String())
+		if _, ok := byName[s.Name]; !ok {
+			byName[s.Name] = s
+		}
+	}
+	server, ok := byName["GET /api/items/:slug"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+		assert.True(t, server.Parent.IsRemote())
+		assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+		assert.Equal(t, int64(200), spanAttribute(server, "http.status_code").AsInt64())
+		assert.Equal(t, "/api/items/:slug", spanAttribute(server, "http.route").AsString())
+	}
+	store, ok := byName["item.Store/GetBySlug"]
+	if assert.True(t, ok) {
+		assert.Equal(t, server.SpanContext.SpanID(), store.Parent.SpanID())
+	}
+	query, ok := byName["SELECT items"]
+	if assert.True(t, ok) {
+		assert.Equal(t, store.SpanContext.SpanID(), query.Parent.SpanID())
+		assert.Equal(t, trace.SpanKindClient, query.SpanKind)
+		assert.Equal(t, "sqlite", spanAttribute(query, "db.system.name").AsString())
+		assert.Equal(t, "SELECT", spanAttribute(query, "db.operation.name").AsString())
+		assert.Contains(t, spanAttribute(query, "db.query.text").AsString(), "items")
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/model"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+const recoveryCodeCount = 10
+
+// EnrollTwoFactor generates a new TOTP secret for the current player. The
+// secret is not enforced until it is confirmed with VerifyTwoFactor.
+func (h *Handler) EnrollTwoFactor(c echo.Context) error {
+	u, err := h.players(c).GetByID(playerIDFromToken(c))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	if u.TOTPEnabled {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, utils.ErrTwoFactorEnabled())
+	}
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	u.TOTPSecret = &secret
+	u.TOTPLastCounter = 0
+	if err := h.players(c).UpdateTOTP(u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newTwoFactorSetupResponse(u))
+}
+
+// VerifyTwoFactor confirms enrollment with a code from the authenticator,
+// enables 2FA and returns a fresh set of recovery codes.
+func (h *Handler) VerifyTwoFactor(c echo.Context) error {
+	u, err := h.players(c).GetByID(playerIDFromToken(c))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	req := &twoFactorVerifyRequest{}
+	if err := req.bind(c); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+	if u.TOTPEnabled {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, utils.ErrTwoFactorEnabled())
+	}
+	if u.TOTPSecret == nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, utils.ErrTwoFactorNotStarted())
+	}
+	now := time.Now()
+	prev := *u
+	if ok, err := h.claimLoginAttempt(c, u, now, false); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if !ok {
+		return nil
+	}
+	counter, ok := utils.ValidateTOTP(*u.TOTPSecret, req.TwoFactor.Code, now)
+	if !ok {
+		if err := h.failLogin(c, u, u.Email, now); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		return utils.RenderError(c, http.StatusUnprocessableEntity, utils.ErrInvalidCode())
+	}
+	if err := h.players(c).ReleaseLoginAttempt(u, &prev); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = utils.HashRecoveryCode(code)
+	}
+	if err := h.players(c).ReplaceRecoveryCodes(u, hashes); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	u.TOTPEnabled = true
+	u.TOTPLastCounter = counter
+	if err := h.players(c).UpdateTOTP(u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, &recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns 2FA off after re-authenticating with both the
+// password and a second factor.
+func (h *Handler) DisableTwoFactor(c echo.Context) error {
+	u, err := h.players(c).GetByID(playerIDFromToken(c))
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil {
+		return utils.RenderError(c, http.StatusNotFound, utils.ErrNotFound())
+	}
+	req := &twoFactorDisableRequest{}
+	if err := req.bind(c); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+	now := time.Now()
+	prev := *u
+	if ok, err := h.claimLoginAttempt(c, u, now, false); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if !ok {
+		return nil
+	}
+	ok := u.CheckPassword(req.TwoFactor.Password)
+	if ok {
+		if ok, err = h.checkSecondFactor(c, u, req.TwoFactor.Code); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+	}
+	if !ok {
+		if err := h.failLogin(c, u, u.Email, now); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+	}
+	if err := h.players(c).ReleaseLoginAttempt(u, &prev); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	u.TOTPEnabled = false
+	u.TOTPSecret = nil
+	u.TOTPLastCounter = 0
+	if err := h.players(c).UpdateTOTP(u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if err := h.players(c).ReplaceRecoveryCodes(u, nil); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newResultResponse())
+}
+
+// LoginTwoFactor completes a two-step login by exchanging the challenge
+// token from Login and a second factor for a session token.
+func (h *Handler) LoginTwoFactor(c echo.Context) error {
+	req := &twoFactorLoginRequest{}
+	if err := req.bind(c); err != nil {
+		return utils.RenderError(c, http.StatusUnprocessableEntity, err)
+	}
+	id, err := utils.ParseChallengeJWT(req.TwoFactor.Challenge)
+	if err != nil {
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+	}
+	u, err := h.players(c).GetByID(id)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if u == nil || !u.TOTPEnabled {
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+	}
+	now := time.Now()
+	if ok, err := h.claimLoginAttempt(c, u, now, false); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	} else if !ok {
+		return nil
+	}
+	ok, err := h.checkSecondFactor(c, u, req.TwoFactor.Code)
+	if err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	if !ok {
+		if err := h.failLogin(c, u, u.Email, now); err != nil {
+			return utils.RenderError(c, http.StatusInternalServerError, err)
+		}
+		return utils.RenderError(c, http.StatusForbidden, utils.ErrAccessForbidden())
+	}
+	if err := h.passLogin(c, u); err != nil {
+		return utils.RenderError(c, http.StatusInternalServerError, err)
+	}
+	r := newPlayerResponse(u)
+	middleware.SetSessionCookies(c, r.Player.Token, utils.SessionTTL)
+	return c.JSON(http.StatusOK, r)
+}
+
+// checkSecondFactor accepts either a TOTP code that has not been used before
+// or an unused recovery code, consuming whichever matched.
+func (h *Handler) checkSecondFactor(c echo.Context, u *model.Player, code string) (bool, error) {
+	if u.TOTPSecret != nil {
+		if counter, ok := utils.ValidateTOTP(*u.TOTPSecret, code, time.Now()); ok {
+			if counter <= u.TOTPLastCounter {
+				return false, nil
+			}
+			u.TOTPLastCounter = counter
+			return true, h.players(c).UpdateTOTP(u)
+		}
+	}
+	return h.players(c).UseRecoveryCode(u.ID, utils.HashRecoveryCode(code))
+}
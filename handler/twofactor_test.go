@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/router/middleware"
+	"golang-starter-pack/utils"
+)
+
+func TestTwoFactorLoginCaseSuccess(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+
+	req := httptest.NewRequest(echo.POST, "/api/player/2fa", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.EnrollTwoFactor)(c))
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	var setupRes twoFactorSetupResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &setupRes))
+	assert.Contains(t, setupRes.TwoFactor.URI, "otpauth://totp/")
+
+	code, err := utils.TOTPCode(setupRes.TwoFactor.Secret, time.Now().Unix()/30)
+	assert.NoError(t, err)
+	req = httptest.NewRequest(echo.POST, "/api/player/2fa/verify", strings.NewReader(`{"twoFactor":{"code":"`+code+`"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.VerifyTwoFactor)(c))
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	var codesRes recoveryCodesResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &codesRes))
+	assert.Len(t, codesRes.RecoveryCodes, recoveryCodeCount)
+
+	req = httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"player1@realworld.io","password":"secret"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.NoError(t, h.Login(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	challenge := responseMap(rec.Body.Bytes(), "challenge")["token"].(string)
+
+	req = httptest.NewRequest(echo.GET, "/api/player", nil)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(challenge))
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.CurrentPlayer)(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	secondStep := func(code string) *httptest.ResponseRecorder {
+		reqJSON := `{"twoFactor":{"challenge":"` + challenge + `","code":"` + code + `"}}`
+		req := httptest.NewRequest(echo.POST, "/api/players/login/2fa", strings.NewReader(reqJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		assert.NoError(t, h.LoginTwoFactor(c))
+		return rec
+	}
+	rec = secondStep(codesRes.RecoveryCodes[0])
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		m := responseMap(rec.Body.Bytes(), "player")
+		assert.Equal(t, "player1", m["username"])
+		assert.NotEmpty(t, m["token"])
+	}
+	assert.Equal(t, http.StatusForbidden, secondStep(codesRes.RecoveryCodes[0]).Code)
+}
+
+func TestDisableTwoFactorCaseWrongPassword(t *testing.T) {
+	tearDown()
+	setup()
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req := httptest.NewRequest(echo.DELETE, "/api/player/2fa", strings.NewReader(`{"twoFactor":{"password":"wrong","code":"000000"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.NoError(t, jwtMiddleware(h.DisableTwoFactor)(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestTwoFactorFailuresCountTowardsLockout(t *testing.T) {
+	tearDown()
+	setup()
+	u, _ := us.GetByID(1)
+	secret, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	u.TOTPSecret = &secret
+	u.TOTPEnabled = true
+	assert.NoError(t, us.UpdateTOTP(u))
+	earlier := time.Now().Add(-time.Hour)
+	u.FailedLogins = 3
+	u.LastFailedLoginAt = &earlier
+	assert.NoError(t, us.UpdateLoginState(u))
+	failures := func() int {
+		u, _ := us.GetByID(1)
+		return u.FailedLogins
+	}
+	wrong := "000000"
+	if valid, _ := utils.TOTPCode(secret, time.Now().Unix()/30); valid == wrong {
+		wrong = "111111"
+	}
+
+	// The password alone does not clear earlier failures.
+	req := httptest.NewRequest(echo.POST, "/api/players/login", strings.NewReader(`{"player":{"email":"player1@realworld.io","password":"secret"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h.Login(e.NewContext(req, rec)))
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	challenge := responseMap(rec.Body.Bytes(), "challenge")["token"].(string)
+	assert.Equal(t, 3, failures())
+
+	jwtMiddleware := middleware.JWT(utils.JWTSecret)
+	req = httptest.NewRequest(echo.DELETE, "/api/player/2fa", strings.NewReader(`{"twoFactor":{"password":"secret","code":"`+wrong+`"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, authHeader(utils.GenerateJWT(1)))
+	rec = httptest.NewRecorder()
+	assert.NoError(t, jwtMiddleware(h.DisableTwoFactor)(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 4, failures())
+
+	secondStep := func(code string) int {
+		req := httptest.NewRequest(echo.POST, "/api/players/login/2fa", strings.NewReader(`{"twoFactor":{"challenge":"`+challenge+`","code":"`+code+`"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h.LoginTwoFactor(e.NewContext(req, rec)))
+		return rec.Code
+	}
+	for failures() < utils.DefaultLoginPolicy.MaxFailures {
+		assert.Equal(t, http.StatusForbidden, secondStep(wrong))
+	}
+	valid, _ := utils.TOTPCode(secret, time.Now().Unix()/30)
+	assert.Equal(t, http.StatusLocked, secondStep(valid))
+}
@@ -4,24 +4,37 @@ import (
 	"golang-starter-pack/model"
 )
 
+// Order selects how item lists are sorted. Ties fall back to recency.
+type Order string
+
+const (
+	OrderRecent    Order = "recent"
+	OrderFavorites Order = "favorites"
+	OrderComments  Order = "comments"
+)
+
 type Store interface {
 	GetBySlug(string) (*model.Item, error)
 	GetPlayerItemBySlug(playerID uint, slug string) (*model.Item, error)
 	CreateItem(*model.Item) error
 	UpdateItem(*model.Item, []string) error
 	DeleteItem(*model.Item) error
-	List(offset, limit int) ([]model.Item, int, error)
-	ListByTag(tag string, offset, limit int) ([]model.Item, int, error)
-	ListByAuthor(username string, offset, limit int) ([]model.Item, int, error)
-	ListByWhoFavorited(username string, offset, limit int) ([]model.Item, int, error)
-	ListFeed(playerID uint, offset, limit int) ([]model.Item, int, error)
+	List(order Order, offset, limit int) ([]model.Item, int, error)
+	// ListByTag, ListByAuthor and ListByWhoFavorited list no items for
+	// unknown tags and players.
+	ListByTag(tag string, order Order, offset, limit int) ([]model.Item, int, error)
+	ListByAuthor(username string, order Order, offset, limit int) ([]model.Item, int, error)
+	ListByWhoFavorited(username string, order Order, offset, limit int) ([]model.Item, int, error)
+	ListFeed(playerID uint, order Order, offset, limit int) ([]model.Item, int, error)
 
 	AddComment(*model.Item, *model.Comment) error
 	GetCommentsBySlug(string) ([]model.Comment, error)
+	CommentsByItems(itemIDs []uint) (map[uint][]model.Comment, error)
 	GetCommentByID(uint) (*model.Comment, error)
 	DeleteComment(*model.Comment) error
 
 	AddFavorite(*model.Item, uint) error
 	RemoveFavorite(*model.Item, uint) error
+	FavoritedBy(playerID uint, itemIDs []uint) (map[uint]bool, error)
 	ListTags() ([]model.Tag, error)
 }
@@ -0,0 +1,192 @@
+package item
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang-starter-pack/model"
+)
+
+// WithTracing wraps s so that every call is traced as a child of the span
+// in ctx. Stores implementing WithContext also trace their SQL beneath it.
+func WithTracing(ctx context.Context, s Store) Store {
+	return &tracedStore{next: s, ctx: ctx}
+}
+
+type tracedStore struct {
+	next Store
+	ctx  context.Context
+}
+
+func (t *tracedStore) start(method string) (Store, trace.Span) {
+	ctx, span := otel.Tracer("golang-starter-pack/item").Start(t.ctx, "item.Store/"+method)
+	if s, ok := t.next.(interface {
+		WithContext(context.Context) Store
+	}); ok {
+		return s.WithContext(ctx), span
+	}
+	return t.next, span
+}
+
+func (t *tracedStore) GetBySlug(slug string) (*model.Item, error) {
+	next, span := t.start("GetBySlug")
+	defer span.End()
+	v, err := next.GetBySlug(slug)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetPlayerItemBySlug(playerID uint, slug string) (*model.Item, error) {
+	next, span := t.start("GetPlayerItemBySlug")
+	defer span.End()
+	v, err := next.GetPlayerItemBySlug(playerID, slug)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) CreateItem(a *model.Item) error {
+	next, span := t.start("CreateItem")
+	defer span.End()
+	err := next.CreateItem(a)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) UpdateItem(a *model.Item, tagList []string) error {
+	next, span := t.start("UpdateItem")
+	defer span.End()
+	err := next.UpdateItem(a, tagList)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) DeleteItem(a *model.Item) error {
+	next, span := t.start("DeleteItem")
+	defer span.End()
+	err := next.DeleteItem(a)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) List(order Order, offset, limit int) ([]model.Item, int, error) {
+	next, span := t.start("List")
+	defer span.End()
+	v0, v1, err := next.List(order, offset, limit)
+	recordError(span, err)
+	return v0, v1, err
+}
+
+func (t *tracedStore) ListByTag(tag string, order Order, offset, limit int) ([]model.Item, int, error) {
+	next, span := t.start("ListByTag")
+	defer span.End()
+	v0, v1, err := next.ListByTag(tag, order, offset, limit)
+	recordError(span, err)
+	return v0, v1, err
+}
+
+func (t *tracedStore) ListByAuthor(username string, order Order, offset, limit int) ([]model.Item, int, error) {
+	next, span := t.start("ListByAuthor")
+	defer span.End()
+	v0, v1, err := next.ListByAuthor(username, order, offset, limit)
+	recordError(span, err)
+	return v0, v1, err
+}
+
+func (t *tracedStore) ListByWhoFavorited(username string, order Order, offset, limit int) ([]model.Item, int, error) {
+	next, span := t.start("ListByWhoFavorited")
+	defer span.End()
+	v0, v1, err := next.ListByWhoFavorited(username, order, offset, limit)
+	recordError(span, err)
+	return v0, v1, err
+}
+
+func (t *tracedStore) ListFeed(playerID uint, order Order, offset, limit int) ([]model.Item, int, error) {
+	next, span := t.start("ListFeed")
+	defer span.End()
+	v0, v1, err := next.ListFeed(playerID, order, offset, limit)
+	recordError(span, err)
+	return v0, v1, err
+}
+
+func (t *tracedStore) AddComment(a *model.Item, cm *model.Comment) error {
+	next, span := t.start("AddComment")
+	defer span.End()
+	err := next.AddComment(a, cm)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) GetCommentsBySlug(slug string) ([]model.Comment, error) {
+	next, span := t.start("GetCommentsBySlug")
+	defer span.End()
+	v, err := next.GetCommentsBySlug(slug)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) CommentsByItems(itemIDs []uint) (map[uint][]model.Comment, error) {
+	next, span := t.start("CommentsByItems")
+	defer span.End()
+	v, err := next.CommentsByItems(itemIDs)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetCommentByID(id uint) (*model.Comment, error) {
+	next, span := t.start("GetCommentByID")
+	defer span.End()
+	v, err := next.GetCommentByID(id)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) DeleteComment(cm *model.Comment) error {
+	next, span := t.start("DeleteComment")
+	defer span.End()
+	err := next.DeleteComment(cm)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) AddFavorite(a *model.Item, playerID uint) error {
+	next, span := t.start("AddFavorite")
+	defer span.End()
+	err := next.AddFavorite(a, playerID)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) RemoveFavorite(a *model.Item, playerID uint) error {
+	next, span := t.start("RemoveFavorite")
+	defer span.End()
+	err := next.RemoveFavorite(a, playerID)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) FavoritedBy(playerID uint, itemIDs []uint) (map[uint]bool, error) {
+	next, span := t.start("FavoritedBy")
+	defer span.End()
+	v, err := next.FavoritedBy(playerID, itemIDs)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) ListTags() ([]model.Tag, error) {
+	next, span := t.start("ListTags")
+	defer span.End()
+	v, err := next.ListTags()
+	recordError(span, err)
+	return v, err
+}
+
+// recordError marks span as failed with err. Nil errors are ignored.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,215 @@
+// Package logging writes leveled, structured log lines, as JSON for log
+// collectors or as readable text for development. Fields describing the
+// current request travel in its context, and values of sensitive fields
+// such as passwords, tokens and emails are redacted before they are written.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+	return levelNames[l]
+}
+
+// ParseLevel accepts the names debug, info, warn and error.
+func ParseLevel(s string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(s, n) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatPretty
+)
+
+// ParseFormat accepts json and pretty.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "pretty", "text":
+		return FormatPretty, nil
+	}
+	return FormatJSON, fmt.Errorf("unknown log format %q", s)
+}
+
+// Logger writes lines carrying its fields. Loggers derived with With and
+// Ctx share their parent's output, so lines never interleave.
+type Logger struct {
+	out    *output
+	fields []interface{}
+}
+
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+	level  Level
+}
+
+// Default is used by the router and by loggers built from a context.
+var Default = New(os.Stderr, FormatJSON, LevelInfo)
+
+// New returns a logger writing lines at level or above to w.
+func New(w io.Writer, format Format, level Level) *Logger {
+	return &Logger{out: &output{w: w, format: format, level: level}}
+}
+
+// With returns a logger adding the given key/value pairs to every line.
+func (l *Logger) With(kv ...interface{}) *Logger {
+	if len(kv) == 0 {
+		return l
+	}
+	fields := make([]interface{}, 0, len(l.fields)+len(kv))
+	return &Logger{out: l.out, fields: append(append(fields, l.fields...), kv...)}
+}
+
+// Ctx returns a logger adding the request fields stored in ctx.
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	return l.With(Fields(ctx)...)
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+func (l *Logger) Debug(msg string, kv ...interface{}) { l.log(LevelDebug, msg, kv) }
+func (l *Logger) Info(msg string, kv ...interface{})  { l.log(LevelInfo, msg, kv) }
+func (l *Logger) Warn(msg string, kv ...interface{})  { l.log(LevelWarn, msg, kv) }
+func (l *Logger) Error(msg string, kv ...interface{}) { l.log(LevelError, msg, kv) }
+
+func (l *Logger) log(level Level, msg string, kv []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	fields := append(append(make([]interface{}, 0, len(l.fields)+len(kv)), l.fields...), kv...)
+	var b bytes.Buffer
+	now := time.Now().UTC()
+	if l.out.format == FormatPretty {
+		writePretty(&b, now, level, msg, fields)
+	} else {
+		writeJSON(&b, now, level, msg, fields)
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(b.Bytes())
+}
+
+func writeJSON(b *bytes.Buffer, now time.Time, level Level, msg string, fields []interface{}) {
+	b.WriteString(`{"time":"` + now.Format(time.RFC3339Nano) + `","level":"` + level.String() + `","msg":`)
+	writeJSONValue(b, redactString(msg))
+	eachField(fields, func(k string, v interface{}) {
+		b.WriteByte(',')
+		writeJSONValue(b, k)
+		b.WriteByte(':')
+		writeJSONValue(b, v)
+	})
+	b.WriteString("}\n")
+}
+
+func writeJSONValue(b *bytes.Buffer, v interface{}) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		j, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(j)
+}
+
+func writePretty(b *bytes.Buffer, now time.Time, level Level, msg string, fields []interface{}) {
+	fmt.Fprintf(b, "%s %-5s %s", now.Format("15:04:05.000"), strings.ToUpper(level.String()), redactString(msg))
+	eachField(fields, func(k string, v interface{}) {
+		s := fmt.Sprint(v)
+		if strings.ContainsAny(s, " \t\n\"=") || s == "" {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(" " + k + "=" + s)
+	})
+	b.WriteByte('\n')
+}
+
+// eachField visits key/value pairs with values made safe to write. A
+// trailing key without a value is reported under "!BADKEY".
+func eachField(fields []interface{}, fn func(k string, v interface{})) {
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 == len(fields) {
+			fn("!BADKEY", redact("", fields[i]))
+			return
+		}
+		k := fmt.Sprint(fields[i])
+		fn(k, redact(k, fields[i+1]))
+	}
+}
+
+// Millis converts d to fractional milliseconds for duration fields.
+func Millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type fieldsKey struct{}
+
+type fieldSet struct {
+	mu sync.Mutex
+	kv []interface{}
+}
+
+// NewContext starts a set of request fields in ctx. Fields added later
+// with AddFields, such as the player once authenticated, are seen by every
+// logger built from a context derived from the returned one.
+func NewContext(ctx context.Context, kv ...interface{}) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &fieldSet{kv: kv})
+}
+
+// AddFields appends to the request fields in ctx, if any.
+func AddFields(ctx context.Context, kv ...interface{}) {
+	if s, ok := ctx.Value(fieldsKey{}).(*fieldSet); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.kv = append(s.kv, kv...)
+	}
+}
+
+// Fields returns the request fields in ctx.
+func Fields(ctx context.Context) []interface{} {
+	s, ok := ctx.Value(fieldsKey{}).(*fieldSet)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]interface{}(nil), s.kv...)
+}
+
+// FromContext returns the Default logger with the request fields in ctx.
+func FromContext(ctx context.Context) *Logger {
+	return Default.Ctx(ctx)
+}
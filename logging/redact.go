@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the values of sensitive fields.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against lowercased field names.
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "apikey", "api_key", "email", "recovery"}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact hides the value of a sensitive key, and emails or tokens that
+// slipped into other text such as error messages.
+func redact(key string, v interface{}) interface{} {
+	if sensitive(key) {
+		return Redacted
+	}
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case string:
+		return redactString(v)
+	case error:
+		return redactString(v.Error())
+	case interface{ String() string }:
+		return redactString(v.String())
+	}
+	// Structs and maps could hold anything, so they are not written.
+	return Redacted
+}
+
+func redactString(s string) string {
+	if !strings.Contains(s, "@") && !strings.Contains(s, "eyJ") {
+		return s
+	}
+	s = emailPattern.ReplaceAllString(s, Redacted)
+	return jwtPattern.ReplaceAllString(s, Redacted)
+}
@@ -1,22 +1,257 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"golang-starter-pack/cache"
 	"golang-starter-pack/db"
 	"golang-starter-pack/handler"
+	"golang-starter-pack/item"
+	"golang-starter-pack/logging"
+	"golang-starter-pack/metrics"
 	"golang-starter-pack/router"
+	"golang-starter-pack/router/middleware"
 	"golang-starter-pack/store"
+	"golang-starter-pack/utils"
+	"golang-starter-pack/version"
+	"google.golang.org/grpc/health"
 )
 
 func main() {
+	logger := newLogger()
+	logging.Default = logger
 	r := router.New()
+	r.HideBanner = true
+	r.Pre(middleware.StripAPIVersion("/api"))
 	v1 := r.Group("/api")
+	if os.Getenv("STRICT_VALIDATION") == "true" {
+		v1.Use(middleware.OpenAPIWithConfig(middleware.OpenAPIConfig{
+			Document:   handler.OpenAPI(),
+			Prefix:     "/api",
+			AllowQuery: []string{"access_token"},
+		}))
+	}
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		if err := utils.DefaultKeySet.LoadKeyDir(dir, os.Getenv("JWT_ACTIVE_KID")); err != nil {
+			fatal(logger, "loading signing keys", err)
+		}
+	}
+
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		if err := middleware.SetTrustedProxies(strings.Split(v, ",")); err != nil {
+			fatal(logger, "parsing TRUSTED_PROXIES", err)
+		}
+	}
+
+	if dir := os.Getenv("I18N_DIR"); dir != "" {
+		if err := utils.Messages.LoadDir(dir); err != nil {
+			fatal(logger, "loading messages", err)
+		}
+	}
 
 	d := db.New()
 	db.AutoMigrate(d)
+	db.Instrument(d)
+	db.Trace(d)
+	db.Log(d, logger)
+
+	tp, err := newTracerProvider()
+	if err != nil {
+		fatal(logger, "setting up tracing", err)
+	}
+	otel.SetTracerProvider(tp)
 
 	us := store.NewPlayerStore(d)
-	as := store.NewItemStore(d)
+	var as item.Store = store.NewItemStore(d)
+	if ttl, err := time.ParseDuration(envOr("CACHE_TTL", "30s")); err != nil {
+		fatal(logger, "parsing CACHE_TTL", err)
+	} else if ttl > 0 {
+		cs := store.NewCachedItemStore(as, newCache(), ttl)
+		metrics.Factory.NewCounterFunc(prometheus.CounterOpts{Name: "item_cache_hits_total", Help: "Item store reads served from the cache."},
+			func() float64 { return float64(cs.Stats().Hits()) })
+		metrics.Factory.NewCounterFunc(prometheus.CounterOpts{Name: "item_cache_misses_total", Help: "Item store reads that missed the cache."},
+			func() float64 { return float64(cs.Stats().Misses()) })
+		as = cs
+	}
 	h := handler.NewHandler(us, as)
+	h.SetLogger(logger)
+	if v := os.Getenv("PUBLIC_URL"); v != "" {
+		u, err := url.Parse(v)
+		if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			err = fmt.Errorf("%q is not an absolute http or https URL", v)
+		}
+		if err != nil {
+			fatal(logger, "parsing PUBLIC_URL", err)
+		}
+		h.SetPublicURL(v)
+	}
+	h.AddReadinessCheck("database", d.DB().Ping)
+	// The schema only changes when the server migrates it at startup, so
+	// it is inspected once rather than on every probe.
+	var migrationsErr error
+	if pending := db.PendingMigrations(d); len(pending) > 0 {
+		migrationsErr = fmt.Errorf("pending: %s", strings.Join(pending, ", "))
+	}
+	h.AddReadinessCheck("migrations", func() error { return migrationsErr })
+	if err := deprecateV1(h); err != nil {
+		fatal(logger, "parsing API_V1_DEPRECATED or API_V1_SUNSET", err)
+	}
 	h.Register(v1)
-	r.Logger.Fatal(r.Start("127.0.0.1:8585"))
+	r.GET("/.well-known/jwks.json", h.JWKS)
+	r.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	r.GET(router.HealthzPath, h.Healthz)
+	r.GET(router.ReadyzPath, h.Readyz)
+	r.GET(router.VersionPath, h.Version)
+
+	delay, err := time.ParseDuration(envOr("DRAIN_DELAY", "5s"))
+	if err != nil {
+		fatal(logger, "parsing DRAIN_DELAY", err)
+	}
+	go func() {
+		logger.Info("listening", "addr", "127.0.0.1:8585", "commit", version.Commit)
+		if err := r.Start("127.0.0.1:8585"); err != nil && err != http.ErrServerClosed {
+			fatal(logger, "server stopped", err)
+		}
+	}()
+
+	// The gRPC API runs on its own port, through the same routes.
+	rpcAddr := envOr("GRPC_ADDR", "127.0.0.1:9090")
+	rpcListener, err := net.Listen("tcp", rpcAddr)
+	if err != nil {
+		fatal(logger, "listening for gRPC", err)
+	}
+	rpcHealth := health.NewServer()
+	rpcServer := handler.NewRPCServer(r, rpcHealth)
+	go func() {
+		logger.Info("serving gRPC", "addr", rpcAddr)
+		if err := rpcServer.Serve(rpcListener); err != nil {
+			fatal(logger, "gRPC server stopped", err)
+		}
+	}()
+
+	// On SIGTERM fail readiness first, so load balancers stop routing here
+	// before in-flight requests are drained.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	h.Drain()
+	rpcHealth.Shutdown()
+	logger.Info("draining", "delay", delay)
+	time.Sleep(delay)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rpcServer.GracefulStop()
+	if err := r.Shutdown(ctx); err != nil {
+		fatal(logger, "shutting down", err)
+	}
+	if err := tp.Shutdown(ctx); err != nil {
+		logger.Error("flushing spans", "err", err)
+	}
+}
+
+// newTracerProvider traces every request, dropping the spans unless
+// TRACE_EXPORTER is stdout or otlp. The OTLP exporter reads the standard
+// OTEL_EXPORTER_OTLP_* variables.
+func newTracerProvider() (*sdktrace.TracerProvider, error) {
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(envOr("OTEL_SERVICE_NAME", "golang-starter-pack")),
+		semconv.ServiceVersion(version.Commit))
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	switch v := os.Getenv("TRACE_EXPORTER"); v {
+	case "":
+	case "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case "otlp":
+		exp, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("unknown TRACE_EXPORTER %q", v)
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// newLogger writes JSON lines unless LOG_FORMAT=pretty, at LOG_LEVEL
+// (default info).
+func newLogger() *logging.Logger {
+	format, err := logging.ParseFormat(envOr("LOG_FORMAT", "json"))
+	if err != nil {
+		fatal(logging.Default, "parsing LOG_FORMAT", err)
+	}
+	level, err := logging.ParseLevel(envOr("LOG_LEVEL", "info"))
+	if err != nil {
+		fatal(logging.Default, "parsing LOG_LEVEL", err)
+	}
+	return logging.New(os.Stderr, format, level)
+}
+
+func fatal(l *logging.Logger, msg string, err error) {
+	l.Error(msg, "err", err)
+	os.Exit(1)
+}
+
+// newCache returns a memcached client when MEMCACHED_ADDR is set and an
+// in-process LRU of CACHE_SIZE entries otherwise.
+func newCache() cache.Cache {
+	if addr := os.Getenv("MEMCACHED_ADDR"); addr != "" {
+		return cache.NewMemcached(addr, 16)
+	}
+	size, err := strconv.Atoi(envOr("CACHE_SIZE", "1000"))
+	if err != nil {
+		size = 1000
+	}
+	return cache.NewLRU(size)
+}
+
+// deprecateV1 marks version 1 of the API deprecated from API_V1_DEPRECATED
+// until API_V1_SUNSET, dates such as 2027-01-31.
+func deprecateV1(h *handler.Handler) error {
+	var at, sunset time.Time
+	var err error
+	if v := os.Getenv("API_V1_DEPRECATED"); v != "" {
+		if at, err = time.Parse("2006-01-02", v); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("API_V1_SUNSET"); v != "" {
+		if sunset, err = time.Parse("2006-01-02", v); err != nil {
+			return err
+		}
+	}
+	if !at.IsZero() || !sunset.IsZero() {
+		h.DeprecateAPIVersion(1, at, sunset)
+	}
+	return nil
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
 }
@@ -0,0 +1,36 @@
+// Package markdown renders CommonMark to HTML that is safe to embed. Raw
+// HTML in the source is dropped, and the output is sanitized so that links
+// and images keep only http, https, mailto and relative URLs.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+var (
+	md     = goldmark.New()
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+// HTML renders src.
+func HTML(src string) string {
+	var b bytes.Buffer
+	if err := md.Convert([]byte(src), &b); err != nil {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return strings.TrimSpace(policy.Sanitize(b.String()))
+}
@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTML(t *testing.T) {
+	for _, tc := range []struct {
+		src, html string
+	}{
+		{"# Title #", "<h1>Title</h1>"},
+		{"Hello *world* and **bold** and `a<b`", "<p>Hello <em>world</em> and <strong>bold</strong> and <code>a&lt;b</code></p>"},
+		{"line  \nbreak", "<p>line<br>\nbreak</p>"},
+		{"- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>"},
+		{"> quote", "<blockquote>\n<p>quote</p>\n</blockquote>"},
+		{"---", "<hr>"},
+		{"```go\nx := 1 < 2\n```", "<pre><code class=\"language-go\">x := 1 &lt; 2\n</code></pre>"},
+		{"    code", "<pre><code>code\n</code></pre>"},
+		{"[x](http://a.com \"t\")", "<p><a href=\"http://a.com\" title=\"t\" rel=\"nofollow\">x</a></p>"},
+		{"[x](mailto:a@b.c)", "<p><a href=\"mailto:a@b.c\" rel=\"nofollow\">x</a></p>"},
+		{"![i](/img.png)", "<p><img src=\"/img.png\" alt=\"i\"></p>"},
+		{"<http://a.com>", "<p><a href=\"http://a.com\" rel=\"nofollow\">http://a.com</a></p>"},
+	} {
+		assert.Equal(t, tc.html, HTML(tc.src), tc.src)
+	}
+}
+
+func TestHTMLEmphasis(t *testing.T) {
+	for _, tc := range []struct {
+		src, html string
+	}{
+		{"***both***", "<p><em><strong>both</strong></em></p>"},
+		{"*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		{"**a *b* c**", "<p><strong>a <em>b</em> c</strong></p>"},
+		{"_a_b_", "<p><em>a_b</em></p>"},
+		{"**unclosed", "<p>**unclosed</p>"},
+	} {
+		assert.Equal(t, tc.html, HTML(tc.src), tc.src)
+	}
+}
+
+func TestHTMLMalformedLinks(t *testing.T) {
+	for _, tc := range []struct {
+		src, html string
+	}{
+		{"[x](http://a.com", "<p>[x](http://a.com</p>"},
+		{"[x]", "<p>[x]</p>"},
+		{"[x][nope]", "<p>[x][nope]</p>"},
+		{"[x](java\tscript:alert(1))", "<p>[x](java\tscript:alert(1))</p>"},
+		{"[x](java\nscript:alert(1))", "<p>[x](java\nscript:alert(1))</p>"},
+	} {
+		assert.Equal(t, tc.html, HTML(tc.src), tc.src)
+	}
+}
+
+func TestHTMLUnsafe(t *testing.T) {
+	for _, tc := range []struct {
+		src, html string
+	}{
+		{"<script>alert(1)</script>", ""},
+		{"<b>bold</b> text", "<p>bold text</p>"},
+		{"a <img src=x onerror=alert(1)> b", "<p>a  b</p>"},
+		{"a & b \"q\"", "<p>a &amp; b &#34;q&#34;</p>"},
+		{"[x](javascript:alert(1))", "<p>x</p>"},
+		{"[x](JaVaScRiPt:alert(1))", "<p>x</p>"},
+		{"[x](jav&#x09;ascript:alert(1))", "<p>x</p>"},
+		{"[x](&#106;avascript:alert(1))", "<p>x</p>"},
+		{"[x](vbscript:msgbox(1))", "<p>x</p>"},
+		{"[x](data:text/html,hi)", "<p>x</p>"},
+		{"<javascript:alert(1)>", "<p>javascript:alert(1)</p>"},
+		{"![x](javascript:alert(1))", "<p><img alt=\"x\"></p>"},
+		{"```go\"><script>\nx\n```", "<pre><code>x\n</code></pre>"},
+	} {
+		assert.Equal(t, tc.html, HTML(tc.src), tc.src)
+	}
+	for _, src := range []string{
+		`[x](http://a.com" onclick="alert(1))`,
+		`[x](http://a.com "t" onclick="alert(1)")`,
+		`![x" onerror="alert(1)](/img.png)`,
+	} {
+		assert.False(t, strings.Contains(HTML(src), `" on`), src)
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	"golang.org/x/crypto/bcrypt"
@@ -17,6 +18,19 @@ type Player struct {
 	Followers  []Follow `gorm:"foreignkey:FollowingID"`
 	Followings []Follow `gorm:"foreignkey:FollowerID"`
 	Favorites  []Item   `gorm:"many2many:favorites;"`
+	Admin      bool     `gorm:"not null;default:false"`
+
+	// Maintained by the store alongside follows.
+	FollowersCount int `gorm:"not null;default:0"`
+
+	FailedLogins      int `gorm:"not null;default:0"`
+	LastFailedLoginAt *time.Time
+	LockedUntil       *time.Time
+
+	TOTPSecret      *string
+	TOTPEnabled     bool  `gorm:"not null;default:false"`
+	TOTPLastCounter int64 `gorm:"not null;default:0"`
+	RecoveryCodes   []RecoveryCode
 }
 
 type Follow struct {
@@ -26,6 +40,23 @@ type Follow struct {
 	FollowingID uint `gorm:"primary_key" sql:"type:int not null"`
 }
 
+// LoginAttempt is the audit trail entry written for every failed login.
+// PlayerID is nil when the email did not match any player.
+type LoginAttempt struct {
+	gorm.Model
+	PlayerID *uint
+	Email    string `gorm:"index"`
+	IP       string `gorm:"index"`
+}
+
+// RecoveryCode is a one-time second factor. Only the hash is stored.
+type RecoveryCode struct {
+	gorm.Model
+	PlayerID uint   `gorm:"index"`
+	CodeHash string `gorm:"not null"`
+	UsedAt   *time.Time
+}
+
 func (p *Player) HashPassword(plain string) (string, error) {
 	if len(plain) == 0 {
 		return "", errors.New("password should not be empty")
@@ -51,3 +82,8 @@ func (u *Player) FollowedBy(id uint) bool {
 	}
 	return false
 }
+
+// IsLocked reports whether the account is locked out at time t.
+func (u *Player) IsLocked(t time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(t)
+}
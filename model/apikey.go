@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	ScopeItemsRead     = "items:read"
+	ScopeItemsWrite    = "items:write"
+	ScopeCommentsWrite = "comments:write"
+	ScopeProfileWrite  = "profile:write"
+)
+
+// APIKey is a long-lived personal credential. Only the hash of the key is
+// stored; Prefix is kept in clear so players can tell their keys apart.
+// Revoked keys are soft deleted.
+type APIKey struct {
+	gorm.Model
+	PlayerID   uint   `gorm:"index;not null"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	KeyHash    string `gorm:"unique_index;not null"`
+	Scopes     string `gorm:"not null"`
+	LastUsedAt *time.Time
+}
+
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *APIKey) SetScopes(scopes []string) {
+	k.Scopes = strings.Join(scopes, ",")
+}
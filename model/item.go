@@ -15,6 +15,10 @@ type Item struct {
 	Comments    []Comment
 	Favorites   []Player `gorm:"many2many:favorites;"`
 	Tags        []Tag    `gorm:"many2many:item_tags;association_autocreate:false"`
+
+	// Maintained by the store alongside favorites and comments.
+	FavoritesCount int `gorm:"not null;default:0;index"`
+	CommentsCount  int `gorm:"not null;default:0;index"`
 }
 
 type Comment struct {
@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TagFunc describes a custom validate tag on s, reporting whether the tag
+// was understood.
+type TagFunc func(tag, param string, s *Schema) bool
+
+// Generator derives schemas from Go types. Named struct types become
+// component schemas of doc and are referenced wherever they appear.
+type Generator struct {
+	doc *Document
+	tag TagFunc
+}
+
+func NewGenerator(doc *Document, tag TagFunc) *Generator {
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = make(map[string]*Schema)
+	}
+	return &Generator{doc: doc, tag: tag}
+}
+
+// Request describes a request body type. Fields are required only when
+// their validate tag says so, and unknown members are not allowed.
+func (g *Generator) Request(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v), true)
+}
+
+// Response describes a response body type. Every field not tagged
+// omitempty is always present, so it is listed as required.
+func (g *Generator) Response(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v), false)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *Generator) schema(t reflect.Type, request bool) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t, nullable = t.Elem(), true
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: intFormat(t), Nullable: nullable}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Format: intFormat(t), Minimum: &zero, Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), request), Nullable: nullable || t.Kind() == reflect.Slice && !request}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem(), request), Nullable: nullable}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, request)
+		}
+		name := componentName(t)
+		if _, ok := g.doc.Components.Schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			g.doc.Components.Schemas[name] = &Schema{}
+			*g.doc.Components.Schemas[name] = *g.object(t, request)
+		}
+		return &Schema{Ref: RefPrefix + name}
+	}
+	return &Schema{}
+}
+
+func intFormat(t reflect.Type) string {
+	if t.Bits() <= 32 {
+		return "int32"
+	}
+	return "int64"
+}
+
+func componentName(t reflect.Type) string {
+	n := t.Name()
+	return strings.ToUpper(n[:1]) + n[1:]
+}
+
+func (g *Generator) object(t reflect.Type, request bool) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	if request {
+		s.AdditionalProperties = false
+	}
+	g.fields(s, t, request)
+	return s
+}
+
+func (g *Generator) fields(s *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		name, omitempty := jsonName(f)
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			g.fields(s, f.Type, request)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := g.schema(f.Type, request)
+		rules := f.Tag.Get("validate")
+		required := g.constrain(fs, rules)
+		// Validators skip the other rules for empty values after omitempty.
+		if request && fs.Type == "string" && strings.HasPrefix(rules, "omitempty") && restrictsStrings(fs) {
+			fs = &Schema{OneOf: []*Schema{{Type: "string", Enum: []interface{}{""}}, fs}}
+		}
+		// A wrapper object is required when anything inside it is.
+		if f.Type.Kind() == reflect.Struct && len(fs.Required) > 0 {
+			required = true
+		}
+		s.Properties[name] = fs
+		if request && required || !request && !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func restrictsStrings(s *Schema) bool {
+	return s.MinLength != nil || s.Pattern != "" || s.Format != ""
+}
+
+func jsonName(f reflect.StructField) (name string, omitempty bool) {
+	tag := f.Tag.Get("json")
+	parts := strings.Split(tag, ",")
+	for _, o := range parts[1:] {
+		if o == "omitempty" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty
+}
+
+// constrain applies validate rules to s, moving to the item schema after
+// "dive", and reports whether the field is required.
+func (g *Generator) constrain(s *Schema, rules string) (required bool) {
+	if rules == "" {
+		return false
+	}
+	target := s
+	for _, rule := range strings.Split(rules, ",") {
+		tag, param := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			tag, param = rule[:i], rule[i+1:]
+		}
+		switch tag {
+		case "required":
+			required = target == s
+			if target.Type == "string" && target.MinLength == nil {
+				one := 1
+				target.MinLength = &one
+			}
+		case "omitempty":
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "min", "max", "len":
+			bound(target, tag, param)
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				target.Enum = append(target.Enum, v)
+			}
+		default:
+			if g.tag != nil {
+				g.tag(tag, param, target)
+			}
+		}
+	}
+	return required
+}
+
+func bound(s *Schema, tag, param string) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+	lo, hi := tag != "max", tag != "min"
+	switch s.Type {
+	case "string":
+		if lo {
+			s.MinLength = &n
+		}
+		if hi {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lo {
+			s.MinItems = &n
+		}
+		if hi {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		f := float64(n)
+		if lo {
+			s.Minimum = &f
+		}
+		if hi {
+			s.Maximum = &f
+		}
+	}
+}
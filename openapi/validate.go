@@ -0,0 +1,234 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Violation locates a value that does not match its schema. Rule names
+// the failed keyword, such as type, required, additionalProperties, enum
+// or maxLength, and Param what the keyword expected.
+type Violation struct {
+	Pointer string
+	Rule    string
+	Param   string
+}
+
+// Validate checks v, decoded with json.Decoder.UseNumber, against s and
+// returns violations in document order. Pointers start at base.
+func (doc *Document) Validate(s *Schema, v interface{}, base string) []Violation {
+	var out []Violation
+	doc.validate(s, v, base, &out)
+	return out
+}
+
+func (doc *Document) validate(s *Schema, v interface{}, ptr string, out *[]Violation) {
+	s = doc.Resolve(s)
+	if s == nil {
+		return
+	}
+	fail := func(rule, param string) {
+		*out = append(*out, Violation{Pointer: ptr, Rule: rule, Param: param})
+	}
+	if v == nil {
+		if !s.Nullable && s.Type != "" {
+			fail("type", s.Type)
+		}
+		return
+	}
+	if len(s.OneOf) > 0 {
+		// Report the alternative the value was evidently meant for: the
+		// one it fails for reasons other than its type or enum.
+		var closest []Violation
+		for _, alt := range s.OneOf {
+			vs := doc.Validate(alt, v, ptr)
+			if len(vs) == 0 {
+				return
+			}
+			if r := vs[0].Rule; vs[0].Pointer != ptr || r != "type" && r != "enum" {
+				closest = vs
+			}
+		}
+		if closest == nil {
+			closest = []Violation{{Pointer: ptr, Rule: "oneOf"}}
+		}
+		*out = append(*out, closest...)
+		return
+	}
+	if !hasType(s.Type, v) {
+		fail("type", s.Type)
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		fail("enum", enumParam(s.Enum))
+		return
+	}
+	switch v := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("minLength", strconv.Itoa(*s.MinLength))
+		} else if s.MaxLength != nil && n > *s.MaxLength {
+			fail("maxLength", strconv.Itoa(*s.MaxLength))
+		} else if s.Pattern != "" && !compiled(s.Pattern).MatchString(v) {
+			fail("pattern", s.Pattern)
+		} else if !validFormat(s.Format, v) {
+			fail("format", s.Format)
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("minimum", strconv.FormatFloat(*s.Minimum, 'g', -1, 64))
+		} else if s.Maximum != nil && f > *s.Maximum {
+			fail("maximum", strconv.FormatFloat(*s.Maximum, 'g', -1, 64))
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("minItems", strconv.Itoa(*s.MinItems))
+		} else if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("maxItems", strconv.Itoa(*s.MaxItems))
+		}
+		for i, e := range v {
+			doc.validate(s.Items, e, ptr+"/"+strconv.Itoa(i), out)
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*out = append(*out, Violation{Pointer: ptr + "/" + escapePointer(name), Rule: "required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p := ptr + "/" + escapePointer(name)
+			if ps, ok := s.Properties[name]; ok {
+				doc.validate(ps, v[name], p, out)
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					*out = append(*out, Violation{Pointer: p, Rule: "additionalProperties"})
+				}
+			case *Schema:
+				doc.validate(extra, v[name], p, out)
+			}
+		}
+	}
+}
+
+func hasType(t string, v interface{}) bool {
+	switch t {
+	case "":
+		return true
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseInt(n.String(), 10, 64)
+		return err == nil
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if n, ok := v.(json.Number); ok {
+			if n.String() == jsonString(e) {
+				return true
+			}
+		} else if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func enumParam(enum []interface{}) string {
+	s := make([]string, len(enum))
+	for i, e := range enum {
+		if str, ok := e.(string); ok {
+			s[i] = str
+		} else {
+			s[i] = jsonString(e)
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+func validFormat(format, v string) bool {
+	switch format {
+	case "email":
+		a, err := mail.ParseAddress(v)
+		return err == nil && a.Address == v
+	case "uri":
+		u, err := url.ParseRequestURI(v)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}
+	return true
+}
+
+var patterns sync.Map
+
+func compiled(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(name string) string {
+	return pointerEscaper.Replace(name)
+}
+
+// ParseParam converts a query or path parameter to the JSON value its
+// schema describes, so it can be validated. Values that cannot be
+// converted are returned unchanged and fail the type check.
+func ParseParam(s *Schema, raw string) interface{} {
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
@@ -0,0 +1,1828 @@
+// The gRPC API. Every method is served by the REST route named in its
+// google.api.http option, so both APIs share validation, authorization
+// and rate limits. Credentials go in the "authorization" metadata, as
+// "Token <jwt>" or "ApiKey <key>".
+//
+// Regenerate the Go code in package pb after editing with "make proto".
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: pb/starter.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ItemOrder int32
+
+const (
+	ItemOrder_ITEM_ORDER_RECENT    ItemOrder = 0
+	ItemOrder_ITEM_ORDER_FAVORITES ItemOrder = 1
+	ItemOrder_ITEM_ORDER_COMMENTS  ItemOrder = 2
+)
+
+// Enum value maps for ItemOrder.
+var (
+	ItemOrder_name = map[int32]string{
+		0: "ITEM_ORDER_RECENT",
+		1: "ITEM_ORDER_FAVORITES",
+		2: "ITEM_ORDER_COMMENTS",
+	}
+	ItemOrder_value = map[string]int32{
+		"ITEM_ORDER_RECENT":    0,
+		"ITEM_ORDER_FAVORITES": 1,
+		"ITEM_ORDER_COMMENTS":  2,
+	}
+)
+
+func (x ItemOrder) Enum() *ItemOrder {
+	p := new(ItemOrder)
+	*p = x
+	return p
+}
+
+func (x ItemOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ItemOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_starter_proto_enumTypes[0].Descriptor()
+}
+
+func (ItemOrder) Type() protoreflect.EnumType {
+	return &file_pb_starter_proto_enumTypes[0]
+}
+
+func (x ItemOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ItemOrder.Descriptor instead.
+func (ItemOrder) EnumDescriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{0}
+}
+
+type Player struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Email    string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Bio      string                 `protobuf:"bytes,3,opt,name=bio,proto3" json:"bio,omitempty"`
+	Image    string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	// A session token, as returned by the REST API.
+	Token         string `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_pb_starter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Player) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Player) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Player) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Player) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Player) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type Profile struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Username       string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Bio            string                 `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`
+	Image          string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Following      bool                   `protobuf:"varint,4,opt,name=following,proto3" json:"following,omitempty"`
+	FollowersCount int32                  `protobuf:"varint,5,opt,name=followers_count,json=followersCount,proto3" json:"followers_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_pb_starter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Profile) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Profile) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Profile) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Profile) GetFollowing() bool {
+	if x != nil {
+		return x.Following
+	}
+	return false
+}
+
+func (x *Profile) GetFollowersCount() int32 {
+	if x != nil {
+		return x.FollowersCount
+	}
+	return 0
+}
+
+type Item struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Slug           string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Body           string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	TagList        []string               `protobuf:"bytes,5,rep,name=tag_list,json=tagList,proto3" json:"tag_list,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Favorited      bool                   `protobuf:"varint,8,opt,name=favorited,proto3" json:"favorited,omitempty"`
+	FavoritesCount int32                  `protobuf:"varint,9,opt,name=favorites_count,json=favoritesCount,proto3" json:"favorites_count,omitempty"`
+	CommentsCount  int32                  `protobuf:"varint,10,opt,name=comments_count,json=commentsCount,proto3" json:"comments_count,omitempty"`
+	Author         *Profile               `protobuf:"bytes,11,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_pb_starter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Item) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Item) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Item) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Item) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Item) GetTagList() []string {
+	if x != nil {
+		return x.TagList
+	}
+	return nil
+}
+
+func (x *Item) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Item) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Item) GetFavorited() bool {
+	if x != nil {
+		return x.Favorited
+	}
+	return false
+}
+
+func (x *Item) GetFavoritesCount() int32 {
+	if x != nil {
+		return x.FavoritesCount
+	}
+	return 0
+}
+
+func (x *Item) GetCommentsCount() int32 {
+	if x != nil {
+		return x.CommentsCount
+	}
+	return 0
+}
+
+func (x *Item) GetAuthor() *Profile {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Author        *Profile               `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_pb_starter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Comment) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Comment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetAuthor() *Profile {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_pb_starter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Players with two-factor authentication get a challenge instead of a
+// player; complete it over REST.
+type LoginResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Player             *Player                `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	ChallengeToken     string                 `protobuf:"bytes,2,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int32                  `protobuf:"varint,3,opt,name=challenge_expires_in,json=challengeExpiresIn,proto3" json:"challenge_expires_in,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_pb_starter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LoginResponse) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetChallengeExpiresIn() int32 {
+	if x != nil {
+		return x.ChallengeExpiresIn
+	}
+	return 0
+}
+
+type GetCurrentPlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentPlayerRequest) Reset() {
+	*x = GetCurrentPlayerRequest{}
+	mi := &file_pb_starter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentPlayerRequest) ProtoMessage() {}
+
+func (x *GetCurrentPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentPlayerRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{6}
+}
+
+type UpdatePlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Bio           string                 `protobuf:"bytes,4,opt,name=bio,proto3" json:"bio,omitempty"`
+	Image         string                 `protobuf:"bytes,5,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePlayerRequest) Reset() {
+	*x = UpdatePlayerRequest{}
+	mi := &file_pb_starter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePlayerRequest) ProtoMessage() {}
+
+func (x *UpdatePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePlayerRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePlayerRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UpdatePlayerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdatePlayerRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UpdatePlayerRequest) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *UpdatePlayerRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+type GetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_pb_starter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProfileRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type FollowPlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FollowPlayerRequest) Reset() {
+	*x = FollowPlayerRequest{}
+	mi := &file_pb_starter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FollowPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FollowPlayerRequest) ProtoMessage() {}
+
+func (x *FollowPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FollowPlayerRequest.ProtoReflect.Descriptor instead.
+func (*FollowPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FollowPlayerRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListItemsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Tag       string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Author    string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Favorited string                 `protobuf:"bytes,3,opt,name=favorited,proto3" json:"favorited,omitempty"`
+	// 20 when left out.
+	Limit         int32     `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32     `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	Sort          ItemOrder `protobuf:"varint,6,opt,name=sort,proto3,enum=starter.v1.ItemOrder" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListItemsRequest) Reset() {
+	*x = ListItemsRequest{}
+	mi := &file_pb_starter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListItemsRequest) ProtoMessage() {}
+
+func (x *ListItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListItemsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListItemsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListItemsRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *ListItemsRequest) GetFavorited() string {
+	if x != nil {
+		return x.Favorited
+	}
+	return ""
+}
+
+func (x *ListItemsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListItemsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListItemsRequest) GetSort() ItemOrder {
+	if x != nil {
+		return x.Sort
+	}
+	return ItemOrder_ITEM_ORDER_RECENT
+}
+
+type GetFeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Sort          ItemOrder              `protobuf:"varint,3,opt,name=sort,proto3,enum=starter.v1.ItemOrder" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeedRequest) Reset() {
+	*x = GetFeedRequest{}
+	mi := &file_pb_starter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeedRequest) ProtoMessage() {}
+
+func (x *GetFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeedRequest.ProtoReflect.Descriptor instead.
+func (*GetFeedRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetFeedRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetFeedRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetFeedRequest) GetSort() ItemOrder {
+	if x != nil {
+		return x.Sort
+	}
+	return ItemOrder_ITEM_ORDER_RECENT
+}
+
+type ListItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Item                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	ItemsCount    int32                  `protobuf:"varint,2,opt,name=items_count,json=itemsCount,proto3" json:"items_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListItemsResponse) Reset() {
+	*x = ListItemsResponse{}
+	mi := &file_pb_starter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListItemsResponse) ProtoMessage() {}
+
+func (x *ListItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListItemsResponse.ProtoReflect.Descriptor instead.
+func (*ListItemsResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListItemsResponse) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListItemsResponse) GetItemsCount() int32 {
+	if x != nil {
+		return x.ItemsCount
+	}
+	return 0
+}
+
+type GetItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetItemRequest) Reset() {
+	*x = GetItemRequest{}
+	mi := &file_pb_starter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetItemRequest) ProtoMessage() {}
+
+func (x *GetItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetItemRequest.ProtoReflect.Descriptor instead.
+func (*GetItemRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetItemRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type CreateItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	TagList       []string               `protobuf:"bytes,4,rep,name=tag_list,json=tagList,proto3" json:"tag_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateItemRequest) Reset() {
+	*x = CreateItemRequest{}
+	mi := &file_pb_starter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateItemRequest) ProtoMessage() {}
+
+func (x *CreateItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateItemRequest.ProtoReflect.Descriptor instead.
+func (*CreateItemRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateItemRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateItemRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateItemRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateItemRequest) GetTagList() []string {
+	if x != nil {
+		return x.TagList
+	}
+	return nil
+}
+
+type UpdateItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	TagList       []string               `protobuf:"bytes,5,rep,name=tag_list,json=tagList,proto3" json:"tag_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateItemRequest) Reset() {
+	*x = UpdateItemRequest{}
+	mi := &file_pb_starter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateItemRequest) ProtoMessage() {}
+
+func (x *UpdateItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateItemRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateItemRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *UpdateItemRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateItemRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateItemRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *UpdateItemRequest) GetTagList() []string {
+	if x != nil {
+		return x.TagList
+	}
+	return nil
+}
+
+type DeleteItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteItemRequest) Reset() {
+	*x = DeleteItemRequest{}
+	mi := &file_pb_starter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteItemRequest) ProtoMessage() {}
+
+func (x *DeleteItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteItemRequest.ProtoReflect.Descriptor instead.
+func (*DeleteItemRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteItemRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type DeleteItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteItemResponse) Reset() {
+	*x = DeleteItemResponse{}
+	mi := &file_pb_starter_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteItemResponse) ProtoMessage() {}
+
+func (x *DeleteItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteItemResponse.ProtoReflect.Descriptor instead.
+func (*DeleteItemResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{17}
+}
+
+type FavoriteItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FavoriteItemRequest) Reset() {
+	*x = FavoriteItemRequest{}
+	mi := &file_pb_starter_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FavoriteItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FavoriteItemRequest) ProtoMessage() {}
+
+func (x *FavoriteItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FavoriteItemRequest.ProtoReflect.Descriptor instead.
+func (*FavoriteItemRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FavoriteItemRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type ListCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsRequest) Reset() {
+	*x = ListCommentsRequest{}
+	mi := &file_pb_starter_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsRequest) ProtoMessage() {}
+
+func (x *ListCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListCommentsRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type ListCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_pb_starter_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+type AddCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCommentRequest) Reset() {
+	*x = AddCommentRequest{}
+	mi := &file_pb_starter_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentRequest) ProtoMessage() {}
+
+func (x *AddCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentRequest.ProtoReflect.Descriptor instead.
+func (*AddCommentRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AddCommentRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *AddCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type DeleteCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_pb_starter_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentRequest) ProtoMessage() {}
+
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteCommentRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *DeleteCommentRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentResponse) Reset() {
+	*x = DeleteCommentResponse{}
+	mi := &file_pb_starter_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentResponse) ProtoMessage() {}
+
+func (x *DeleteCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommentResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{23}
+}
+
+type ListTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_pb_starter_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{24}
+}
+
+type ListTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_pb_starter_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_starter_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_pb_starter_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListTagsResponse) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_pb_starter_proto protoreflect.FileDescriptor
+
+const file_pb_starter_proto_rawDesc = "" +
+	"\n" +
+	"\x10pb/starter.proto\x12\n" +
+	"starter.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"x\n" +
+	"\x06Player\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x10\n" +
+	"\x03bio\x18\x03 \x01(\tR\x03bio\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x14\n" +
+	"\x05token\x18\x05 \x01(\tR\x05token\"\x94\x01\n" +
+	"\aProfile\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x10\n" +
+	"\x03bio\x18\x02 \x01(\tR\x03bio\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1c\n" +
+	"\tfollowing\x18\x04 \x01(\bR\tfollowing\x12'\n" +
+	"\x0ffollowers_count\x18\x05 \x01(\x05R\x0efollowersCount\"\x92\x03\n" +
+	"\x04Item\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x19\n" +
+	"\btag_list\x18\x05 \x03(\tR\atagList\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1c\n" +
+	"\tfavorited\x18\b \x01(\bR\tfavorited\x12'\n" +
+	"\x0ffavorites_count\x18\t \x01(\x05R\x0efavoritesCount\x12%\n" +
+	"\x0ecomments_count\x18\n" +
+	" \x01(\x05R\rcommentsCount\x12+\n" +
+	"\x06author\x18\v \x01(\v2\x13.starter.v1.ProfileR\x06author\"\xd0\x01\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12+\n" +
+	"\x06author\x18\x05 \x01(\v2\x13.starter.v1.ProfileR\x06author\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x96\x01\n" +
+	"\rLoginResponse\x12*\n" +
+	"\x06player\x18\x01 \x01(\v2\x12.starter.v1.PlayerR\x06player\x12'\n" +
+	"\x0fchallenge_token\x18\x02 \x01(\tR\x0echallengeToken\x120\n" +
+	"\x14challenge_expires_in\x18\x03 \x01(\x05R\x12challengeExpiresIn\"\x19\n" +
+	"\x17GetCurrentPlayerRequest\"\x8b\x01\n" +
+	"\x13UpdatePlayerRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x10\n" +
+	"\x03bio\x18\x04 \x01(\tR\x03bio\x12\x14\n" +
+	"\x05image\x18\x05 \x01(\tR\x05image\"/\n" +
+	"\x11GetProfileRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"1\n" +
+	"\x13FollowPlayerRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xb3\x01\n" +
+	"\x10ListItemsRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12\x1c\n" +
+	"\tfavorited\x18\x03 \x01(\tR\tfavorited\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\x12)\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\x15.starter.v1.ItemOrderR\x04sort\"i\n" +
+	"\x0eGetFeedRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12)\n" +
+	"\x04sort\x18\x03 \x01(\x0e2\x15.starter.v1.ItemOrderR\x04sort\"\\\n" +
+	"\x11ListItemsResponse\x12&\n" +
+	"\x05items\x18\x01 \x03(\v2\x10.starter.v1.ItemR\x05items\x12\x1f\n" +
+	"\vitems_count\x18\x02 \x01(\x05R\n" +
+	"itemsCount\"$\n" +
+	"\x0eGetItemRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\"z\n" +
+	"\x11CreateItemRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x19\n" +
+	"\btag_list\x18\x04 \x03(\tR\atagList\"\x8e\x01\n" +
+	"\x11UpdateItemRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x19\n" +
+	"\btag_list\x18\x05 \x03(\tR\atagList\"'\n" +
+	"\x11DeleteItemRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\"\x14\n" +
+	"\x12DeleteItemResponse\")\n" +
+	"\x13FavoriteItemRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\")\n" +
+	"\x13ListCommentsRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\"G\n" +
+	"\x14ListCommentsResponse\x12/\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.starter.v1.CommentR\bcomments\";\n" +
+	"\x11AddCommentRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\":\n" +
+	"\x14DeleteCommentRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"\x17\n" +
+	"\x15DeleteCommentResponse\"\x11\n" +
+	"\x0fListTagsRequest\"&\n" +
+	"\x10ListTagsResponse\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags*U\n" +
+	"\tItemOrder\x12\x15\n" +
+	"\x11ITEM_ORDER_RECENT\x10\x00\x12\x18\n" +
+	"\x14ITEM_ORDER_FAVORITES\x10\x01\x12\x17\n" +
+	"\x13ITEM_ORDER_COMMENTS\x10\x022\xe9\x04\n" +
+	"\aPlayers\x12[\n" +
+	"\x05Login\x12\x18.starter.v1.LoginRequest\x1a\x19.starter.v1.LoginResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/players/login\x12`\n" +
+	"\x10GetCurrentPlayer\x12#.starter.v1.GetCurrentPlayerRequest\x1a\x12.starter.v1.Player\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/api/player\x12[\n" +
+	"\fUpdatePlayer\x12\x1f.starter.v1.UpdatePlayerRequest\x1a\x12.starter.v1.Player\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*2\v/api/player\x12b\n" +
+	"\n" +
+	"GetProfile\x12\x1d.starter.v1.GetProfileRequest\x1a\x13.starter.v1.Profile\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/api/profiles/{username}\x12m\n" +
+	"\fFollowPlayer\x12\x1f.starter.v1.FollowPlayerRequest\x1a\x13.starter.v1.Profile\"'\x82\xd3\xe4\x93\x02!\"\x1f/api/profiles/{username}/follow\x12o\n" +
+	"\x0eUnfollowPlayer\x12\x1f.starter.v1.FollowPlayerRequest\x1a\x13.starter.v1.Profile\"'\x82\xd3\xe4\x93\x02!*\x1f/api/profiles/{username}/follow2\x83\x06\n" +
+	"\x05Items\x12\\\n" +
+	"\tListItems\x12\x1c.starter.v1.ListItemsRequest\x1a\x1d.starter.v1.ListItemsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/api/items\x12]\n" +
+	"\aGetFeed\x12\x1a.starter.v1.GetFeedRequest\x1a\x1d.starter.v1.ListItemsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/items/feed\x12R\n" +
+	"\aGetItem\x12\x1a.starter.v1.GetItemRequest\x1a\x10.starter.v1.Item\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/items/{slug}\x12T\n" +
+	"\n" +
+	"CreateItem\x12\x1d.starter.v1.CreateItemRequest\x1a\x10.starter.v1.Item\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/api/items\x12[\n" +
+	"\n" +
+	"UpdateItem\x12\x1d.starter.v1.UpdateItemRequest\x1a\x10.starter.v1.Item\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*2\x11/api/items/{slug}\x12f\n" +
+	"\n" +
+	"DeleteItem\x12\x1d.starter.v1.DeleteItemRequest\x1a\x1e.starter.v1.DeleteItemResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/api/items/{slug}\x12e\n" +
+	"\fFavoriteItem\x12\x1f.starter.v1.FavoriteItemRequest\x1a\x10.starter.v1.Item\"\"\x82\xd3\xe4\x93\x02\x1c\"\x1a/api/items/{slug}/favorite\x12g\n" +
+	"\x0eUnfavoriteItem\x12\x1f.starter.v1.FavoriteItemRequest\x1a\x10.starter.v1.Item\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/api/items/{slug}/favorite2\xe9\x02\n" +
+	"\bComments\x12u\n" +
+	"\fListComments\x12\x1f.starter.v1.ListCommentsRequest\x1a .starter.v1.ListCommentsResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/items/{slug}/comments\x12g\n" +
+	"\n" +
+	"AddComment\x12\x1d.starter.v1.AddCommentRequest\x1a\x13.starter.v1.Comment\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/api/items/{slug}/comments\x12}\n" +
+	"\rDeleteComment\x12 .starter.v1.DeleteCommentRequest\x1a!.starter.v1.DeleteCommentResponse\"'\x82\xd3\xe4\x93\x02!*\x1f/api/items/{slug}/comments/{id}2`\n" +
+	"\x04Tags\x12X\n" +
+	"\bListTags\x12\x1b.starter.v1.ListTagsRequest\x1a\x1c.starter.v1.ListTagsResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/api/tagsB\x18Z\x16golang-starter-pack/pbb\x06proto3"
+
+var (
+	file_pb_starter_proto_rawDescOnce sync.Once
+	file_pb_starter_proto_rawDescData []byte
+)
+
+func file_pb_starter_proto_rawDescGZIP() []byte {
+	file_pb_starter_proto_rawDescOnce.Do(func() {
+		file_pb_starter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pb_starter_proto_rawDesc), len(file_pb_starter_proto_rawDesc)))
+	})
+	return file_pb_starter_proto_rawDescData
+}
+
+var file_pb_starter_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pb_starter_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_pb_starter_proto_goTypes = []any{
+	(ItemOrder)(0),                  // 0: starter.v1.ItemOrder
+	(*Player)(nil),                  // 1: starter.v1.Player
+	(*Profile)(nil),                 // 2: starter.v1.Profile
+	(*Item)(nil),                    // 3: starter.v1.Item
+	(*Comment)(nil),                 // 4: starter.v1.Comment
+	(*LoginRequest)(nil),            // 5: starter.v1.LoginRequest
+	(*LoginResponse)(nil),           // 6: starter.v1.LoginResponse
+	(*GetCurrentPlayerRequest)(nil), // 7: starter.v1.GetCurrentPlayerRequest
+	(*UpdatePlayerRequest)(nil),     // 8: starter.v1.UpdatePlayerRequest
+	(*GetProfileRequest)(nil),       // 9: starter.v1.GetProfileRequest
+	(*FollowPlayerRequest)(nil),     // 10: starter.v1.FollowPlayerRequest
+	(*ListItemsRequest)(nil),        // 11: starter.v1.ListItemsRequest
+	(*GetFeedRequest)(nil),          // 12: starter.v1.GetFeedRequest
+	(*ListItemsResponse)(nil),       // 13: starter.v1.ListItemsResponse
+	(*GetItemRequest)(nil),          // 14: starter.v1.GetItemRequest
+	(*CreateItemRequest)(nil),       // 15: starter.v1.CreateItemRequest
+	(*UpdateItemRequest)(nil),       // 16: starter.v1.UpdateItemRequest
+	(*DeleteItemRequest)(nil),       // 17: starter.v1.DeleteItemRequest
+	(*DeleteItemResponse)(nil),      // 18: starter.v1.DeleteItemResponse
+	(*FavoriteItemRequest)(nil),     // 19: starter.v1.FavoriteItemRequest
+	(*ListCommentsRequest)(nil),     // 20: starter.v1.ListCommentsRequest
+	(*ListCommentsResponse)(nil),    // 21: starter.v1.ListCommentsResponse
+	(*AddCommentRequest)(nil),       // 22: starter.v1.AddCommentRequest
+	(*DeleteCommentRequest)(nil),    // 23: starter.v1.DeleteCommentRequest
+	(*DeleteCommentResponse)(nil),   // 24: starter.v1.DeleteCommentResponse
+	(*ListTagsRequest)(nil),         // 25: starter.v1.ListTagsRequest
+	(*ListTagsResponse)(nil),        // 26: starter.v1.ListTagsResponse
+	(*timestamppb.Timestamp)(nil),   // 27: google.protobuf.Timestamp
+}
+var file_pb_starter_proto_depIdxs = []int32{
+	27, // 0: starter.v1.Item.created_at:type_name -> google.protobuf.Timestamp
+	27, // 1: starter.v1.Item.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: starter.v1.Item.author:type_name -> starter.v1.Profile
+	27, // 3: starter.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	27, // 4: starter.v1.Comment.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 5: starter.v1.Comment.author:type_name -> starter.v1.Profile
+	1,  // 6: starter.v1.LoginResponse.player:type_name -> starter.v1.Player
+	0,  // 7: starter.v1.ListItemsRequest.sort:type_name -> starter.v1.ItemOrder
+	0,  // 8: starter.v1.GetFeedRequest.sort:type_name -> starter.v1.ItemOrder
+	3,  // 9: starter.v1.ListItemsResponse.items:type_name -> starter.v1.Item
+	4,  // 10: starter.v1.ListCommentsResponse.comments:type_name -> starter.v1.Comment
+	5,  // 11: starter.v1.Players.Login:input_type -> starter.v1.LoginRequest
+	7,  // 12: starter.v1.Players.GetCurrentPlayer:input_type -> starter.v1.GetCurrentPlayerRequest
+	8,  // 13: starter.v1.Players.UpdatePlayer:input_type -> starter.v1.UpdatePlayerRequest
+	9,  // 14: starter.v1.Players.GetProfile:input_type -> starter.v1.GetProfileRequest
+	10, // 15: starter.v1.Players.FollowPlayer:input_type -> starter.v1.FollowPlayerRequest
+	10, // 16: starter.v1.Players.UnfollowPlayer:input_type -> starter.v1.FollowPlayerRequest
+	11, // 17: starter.v1.Items.ListItems:input_type -> starter.v1.ListItemsRequest
+	12, // 18: starter.v1.Items.GetFeed:input_type -> starter.v1.GetFeedRequest
+	14, // 19: starter.v1.Items.GetItem:input_type -> starter.v1.GetItemRequest
+	15, // 20: starter.v1.Items.CreateItem:input_type -> starter.v1.CreateItemRequest
+	16, // 21: starter.v1.Items.UpdateItem:input_type -> starter.v1.UpdateItemRequest
+	17, // 22: starter.v1.Items.DeleteItem:input_type -> starter.v1.DeleteItemRequest
+	19, // 23: starter.v1.Items.FavoriteItem:input_type -> starter.v1.FavoriteItemRequest
+	19, // 24: starter.v1.Items.UnfavoriteItem:input_type -> starter.v1.FavoriteItemRequest
+	20, // 25: starter.v1.Comments.ListComments:input_type -> starter.v1.ListCommentsRequest
+	22, // 26: starter.v1.Comments.AddComment:input_type -> starter.v1.AddCommentRequest
+	23, // 27: starter.v1.Comments.DeleteComment:input_type -> starter.v1.DeleteCommentRequest
+	25, // 28: starter.v1.Tags.ListTags:input_type -> starter.v1.ListTagsRequest
+	6,  // 29: starter.v1.Players.Login:output_type -> starter.v1.LoginResponse
+	1,  // 30: starter.v1.Players.GetCurrentPlayer:output_type -> starter.v1.Player
+	1,  // 31: starter.v1.Players.UpdatePlayer:output_type -> starter.v1.Player
+	2,  // 32: starter.v1.Players.GetProfile:output_type -> starter.v1.Profile
+	2,  // 33: starter.v1.Players.FollowPlayer:output_type -> starter.v1.Profile
+	2,  // 34: starter.v1.Players.UnfollowPlayer:output_type -> starter.v1.Profile
+	13, // 35: starter.v1.Items.ListItems:output_type -> starter.v1.ListItemsResponse
+	13, // 36: starter.v1.Items.GetFeed:output_type -> starter.v1.ListItemsResponse
+	3,  // 37: starter.v1.Items.GetItem:output_type -> starter.v1.Item
+	3,  // 38: starter.v1.Items.CreateItem:output_type -> starter.v1.Item
+	3,  // 39: starter.v1.Items.UpdateItem:output_type -> starter.v1.Item
+	18, // 40: starter.v1.Items.DeleteItem:output_type -> starter.v1.DeleteItemResponse
+	3,  // 41: starter.v1.Items.FavoriteItem:output_type -> starter.v1.Item
+	3,  // 42: starter.v1.Items.UnfavoriteItem:output_type -> starter.v1.Item
+	21, // 43: starter.v1.Comments.ListComments:output_type -> starter.v1.ListCommentsResponse
+	4,  // 44: starter.v1.Comments.AddComment:output_type -> starter.v1.Comment
+	24, // 45: starter.v1.Comments.DeleteComment:output_type -> starter.v1.DeleteCommentResponse
+	26, // 46: starter.v1.Tags.ListTags:output_type -> starter.v1.ListTagsResponse
+	29, // [29:47] is the sub-list for method output_type
+	11, // [11:29] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_pb_starter_proto_init() }
+func file_pb_starter_proto_init() {
+	if File_pb_starter_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_starter_proto_rawDesc), len(file_pb_starter_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_pb_starter_proto_goTypes,
+		DependencyIndexes: file_pb_starter_proto_depIdxs,
+		EnumInfos:         file_pb_starter_proto_enumTypes,
+		MessageInfos:      file_pb_starter_proto_msgTypes,
+	}.Build()
+	File_pb_starter_proto = out.File
+	file_pb_starter_proto_goTypes = nil
+	file_pb_starter_proto_depIdxs = nil
+}
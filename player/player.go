@@ -1,6 +1,8 @@
 package player
 
 import (
+	"time"
+
 	"golang-starter-pack/model"
 )
 
@@ -13,4 +15,23 @@ type Store interface {
 	AddFollower(player *model.Player, followerID uint) error
 	RemoveFollower(player *model.Player, followerID uint) error
 	IsFollower(playerID, followerID uint) (bool, error)
+	FollowingSet(followerID uint, playerIDs []uint) (map[uint]bool, error)
+
+	AddLoginAttempt(*model.LoginAttempt) error
+	CountFailedLoginsByIP(ip string, since time.Time) (int, error)
+	FailedLoginsByEmail(email string) (int, *time.Time, error)
+	ClaimLoginAttempt(player *model.Player, at time.Time, lockedUntil *time.Time) (bool, error)
+	ReleaseLoginAttempt(player, prev *model.Player) error
+	UpdateLoginState(*model.Player) error
+
+	UpdateTOTP(*model.Player) error
+	ReplaceRecoveryCodes(player *model.Player, hashes []string) error
+	UseRecoveryCode(playerID uint, hash string) (bool, error)
+
+	CreateAPIKey(*model.APIKey) error
+	ListAPIKeys(playerID uint) ([]model.APIKey, error)
+	GetAPIKey(playerID, id uint) (*model.APIKey, error)
+	GetAPIKeyByHash(string) (*model.APIKey, error)
+	TouchAPIKey(*model.APIKey) error
+	RevokeAPIKey(*model.APIKey) error
 }
@@ -0,0 +1,233 @@
+package player
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang-starter-pack/model"
+)
+
+// WithTracing wraps s so that every call is traced as a child of the span
+// in ctx. Stores implementing WithContext also trace their SQL beneath it.
+func WithTracing(ctx context.Context, s Store) Store {
+	return &tracedStore{next: s, ctx: ctx}
+}
+
+type tracedStore struct {
+	next Store
+	ctx  context.Context
+}
+
+func (t *tracedStore) start(method string) (Store, trace.Span) {
+	ctx, span := otel.Tracer("golang-starter-pack/player").Start(t.ctx, "player.Store/"+method)
+	if s, ok := t.next.(interface {
+		WithContext(context.Context) Store
+	}); ok {
+		return s.WithContext(ctx), span
+	}
+	return t.next, span
+}
+
+func (t *tracedStore) GetByID(id uint) (*model.Player, error) {
+	next, span := t.start("GetByID")
+	defer span.End()
+	v, err := next.GetByID(id)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetByEmail(email string) (*model.Player, error) {
+	next, span := t.start("GetByEmail")
+	defer span.End()
+	v, err := next.GetByEmail(email)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetByUsername(username string) (*model.Player, error) {
+	next, span := t.start("GetByUsername")
+	defer span.End()
+	v, err := next.GetByUsername(username)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) Create(u *model.Player) error {
+	next, span := t.start("Create")
+	defer span.End()
+	err := next.Create(u)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) Update(u *model.Player) error {
+	next, span := t.start("Update")
+	defer span.End()
+	err := next.Update(u)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) AddFollower(u *model.Player, followerID uint) error {
+	next, span := t.start("AddFollower")
+	defer span.End()
+	err := next.AddFollower(u, followerID)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) RemoveFollower(u *model.Player, followerID uint) error {
+	next, span := t.start("RemoveFollower")
+	defer span.End()
+	err := next.RemoveFollower(u, followerID)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) IsFollower(playerID, followerID uint) (bool, error) {
+	next, span := t.start("IsFollower")
+	defer span.End()
+	v, err := next.IsFollower(playerID, followerID)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) FollowingSet(followerID uint, playerIDs []uint) (map[uint]bool, error) {
+	next, span := t.start("FollowingSet")
+	defer span.End()
+	v, err := next.FollowingSet(followerID, playerIDs)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) AddLoginAttempt(a *model.LoginAttempt) error {
+	next, span := t.start("AddLoginAttempt")
+	defer span.End()
+	err := next.AddLoginAttempt(a)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) CountFailedLoginsByIP(ip string, since time.Time) (int, error) {
+	next, span := t.start("CountFailedLoginsByIP")
+	defer span.End()
+	v, err := next.CountFailedLoginsByIP(ip, since)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) FailedLoginsByEmail(email string) (int, *time.Time, error) {
+	next, span := t.start("FailedLoginsByEmail")
+	defer span.End()
+	n, last, err := next.FailedLoginsByEmail(email)
+	recordError(span, err)
+	return n, last, err
+}
+
+func (t *tracedStore) ClaimLoginAttempt(u *model.Player, at time.Time, lockedUntil *time.Time) (bool, error) {
+	next, span := t.start("ClaimLoginAttempt")
+	defer span.End()
+	v, err := next.ClaimLoginAttempt(u, at, lockedUntil)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) ReleaseLoginAttempt(u, prev *model.Player) error {
+	next, span := t.start("ReleaseLoginAttempt")
+	defer span.End()
+	err := next.ReleaseLoginAttempt(u, prev)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) UpdateLoginState(u *model.Player) error {
+	next, span := t.start("UpdateLoginState")
+	defer span.End()
+	err := next.UpdateLoginState(u)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) UpdateTOTP(u *model.Player) error {
+	next, span := t.start("UpdateTOTP")
+	defer span.End()
+	err := next.UpdateTOTP(u)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) ReplaceRecoveryCodes(u *model.Player, hashes []string) error {
+	next, span := t.start("ReplaceRecoveryCodes")
+	defer span.End()
+	err := next.ReplaceRecoveryCodes(u, hashes)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) UseRecoveryCode(playerID uint, hash string) (bool, error) {
+	next, span := t.start("UseRecoveryCode")
+	defer span.End()
+	v, err := next.UseRecoveryCode(playerID, hash)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) CreateAPIKey(k *model.APIKey) error {
+	next, span := t.start("CreateAPIKey")
+	defer span.End()
+	err := next.CreateAPIKey(k)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) ListAPIKeys(playerID uint) ([]model.APIKey, error) {
+	next, span := t.start("ListAPIKeys")
+	defer span.End()
+	v, err := next.ListAPIKeys(playerID)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetAPIKey(playerID, id uint) (*model.APIKey, error) {
+	next, span := t.start("GetAPIKey")
+	defer span.End()
+	v, err := next.GetAPIKey(playerID, id)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	next, span := t.start("GetAPIKeyByHash")
+	defer span.End()
+	v, err := next.GetAPIKeyByHash(hash)
+	recordError(span, err)
+	return v, err
+}
+
+func (t *tracedStore) TouchAPIKey(k *model.APIKey) error {
+	next, span := t.start("TouchAPIKey")
+	defer span.End()
+	err := next.TouchAPIKey(k)
+	recordError(span, err)
+	return err
+}
+
+func (t *tracedStore) RevokeAPIKey(k *model.APIKey) error {
+	next, span := t.start("RevokeAPIKey")
+	defer span.End()
+	err := next.RevokeAPIKey(k)
+	recordError(span, err)
+	return err
+}
+
+// recordError marks span as failed with err. Nil errors are ignored.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,20 @@
+package render
+
+import "github.com/fxamacker/cbor/v2"
+
+// cborMode encodes timestamps as the RFC 3339 strings JSON carries and
+// sorts map keys so that equal values encode to equal bytes. Struct fields
+// are named by their json tags when they have no cbor tag.
+var cborMode = func() cbor.EncMode {
+	m, err := cbor.EncOptions{Sort: cbor.SortCoreDeterministic, Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return m
+}()
+
+// MarshalCBOR encodes v as CBOR with the field names of its JSON form,
+// using the smallest encoding of each integer.
+func MarshalCBOR(v interface{}) ([]byte, error) {
+	return cborMode.Marshal(v)
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+)
+
+// Table is implemented by responses that list records, which can then be
+// rendered as CSV: a header row followed by one row per record.
+type Table interface {
+	Table() (header []string, rows [][]string)
+}
+
+// ErrNotTable is returned when CSV is asked of a value that is no Table.
+var ErrNotTable = errors.New("render: value has no CSV form")
+
+// MarshalCSV encodes a Table as CSV. Cells that a spreadsheet would run
+// as a formula are quoted with a leading apostrophe.
+func MarshalCSV(v interface{}) ([]byte, error) {
+	t, ok := v.(Table)
+	if !ok {
+		return nil, ErrNotTable
+	}
+	header, rows := t.Table()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(header)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = escapeFormula(cell)
+		}
+		w.Write(cells)
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// escapeFormula defuses CSV injection: spreadsheets evaluate cells that
+// start with =, +, - or @, and some also those that start with a tab or
+// carriage return.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	// MessagePack has a timestamp extension, but timestamps are rendered
+	// as the RFC 3339 strings JSON carries.
+	msgpack.Register(time.Time{}, func(e *msgpack.Encoder, v reflect.Value) error {
+		return e.EncodeString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+	}, nil)
+}
+
+// MarshalMsgPack encodes v as MessagePack with the field names of its JSON
+// form, using the smallest encoding of each integer. Keys of maps holding
+// strings, bools or interface values are sorted so that equal values
+// encode to equal bytes; the encoder leaves other maps in Go's order.
+func MarshalMsgPack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+// offers lists the formats in order of preference with the media types
+// and structured syntax suffixes (RFC 6839) each answers to.
+var offers = []struct {
+	format *Format
+	types  []string
+	suffix string
+}{
+	{JSON, []string{MIMEJSON}, "+json"},
+	{MsgPack, []string{MIMEMsgPack, "application/x-msgpack", "application/vnd.msgpack"}, "+msgpack"},
+	{CBOR, []string{MIMECBOR}, "+cbor"},
+	{CSV, []string{MIMECSV}, ""},
+}
+
+// Negotiate picks the format of v the Accept header prefers, JSON when it
+// is empty and nil when it accepts none. CSV is only offered for a Table.
+func Negotiate(accept string, v interface{}) *Format {
+	if strings.TrimSpace(accept) == "" {
+		return JSON
+	}
+	ranges := parseAccept(accept)
+	_, table := v.(Table)
+	var (
+		best  *Format
+		bestQ float64
+	)
+	for _, o := range offers {
+		if o.format == CSV && !table {
+			continue
+		}
+		if q := quality(ranges, o.types, o.suffix); q > bestQ {
+			best, bestQ = o.format, q
+		}
+	}
+	return best
+}
+
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		t := strings.ToLower(strings.TrimSpace(params[0]))
+		slash := strings.IndexByte(t, '/')
+		if slash < 0 {
+			continue
+		}
+		r := mediaRange{typ: t[:slash], subtype: t[slash+1:], q: 1}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// quality is the q of the most specific range matching any of types, or
+// ending in suffix.
+func quality(ranges []mediaRange, types []string, suffix string) float64 {
+	q, specificity := 0.0, -1
+	for _, t := range types {
+		slash := strings.IndexByte(t, '/')
+		typ, subtype := t[:slash], t[slash+1:]
+		for _, r := range ranges {
+			s := -1
+			switch {
+			case r.typ == typ && (r.subtype == subtype || suffix != "" && strings.HasSuffix(r.subtype, suffix)):
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+	}
+	return q
+}
@@ -0,0 +1,31 @@
+// Package render encodes API responses as JSON, MessagePack, CBOR or CSV.
+//
+// The binary formats name fields by their json tags and honor omitempty,
+// so they carry the same fields as JSON, and encode timestamps as RFC 3339
+// strings like JSON does.
+package render
+
+import "encoding/json"
+
+// Media types of the formats. MessagePack is also accepted as
+// application/x-msgpack and application/vnd.msgpack.
+const (
+	MIMEJSON    = "application/json"
+	MIMEMsgPack = "application/msgpack"
+	MIMECBOR    = "application/cbor"
+	MIMECSV     = "text/csv"
+)
+
+// Format encodes values in one media type.
+type Format struct {
+	Name        string
+	ContentType string
+	Marshal     func(v interface{}) ([]byte, error)
+}
+
+var (
+	JSON    = &Format{Name: "json", ContentType: MIMEJSON + "; charset=UTF-8", Marshal: json.Marshal}
+	MsgPack = &Format{Name: "msgpack", ContentType: MIMEMsgPack, Marshal: MarshalMsgPack}
+	CBOR    = &Format{Name: "cbor", ContentType: MIMECBOR, Marshal: MarshalCBOR}
+	CSV     = &Format{Name: "csv", ContentType: MIMECSV + "; charset=UTF-8; header=present", Marshal: MarshalCSV}
+)
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type record struct {
+	Name      string     `json:"name"`
+	Note      string     `json:"note,omitempty"`
+	Hidden    string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+type table [][]string
+
+func (t table) Table() ([]string, [][]string) {
+	return []string{"name"}, t
+}
+
+func TestMarshalBinary(t *testing.T) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+	v := &record{Name: "a", Hidden: "secret", CreatedAt: at}
+	want := map[string]interface{}{"name": "a", "createdAt": "2020-01-02T03:04:05.0000006Z", "deletedAt": nil}
+
+	b, err := MarshalMsgPack(v)
+	if assert.NoError(t, err) {
+		var got map[string]interface{}
+		assert.NoError(t, msgpack.Unmarshal(b, &got))
+		assert.Equal(t, want, got)
+	}
+	b, err = MarshalCBOR(v)
+	if assert.NoError(t, err) {
+		var got map[string]interface{}
+		assert.NoError(t, cbor.Unmarshal(b, &got))
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestMarshalBinaryMapOrder(t *testing.T) {
+	m := map[string]interface{}{"b": 2, "a": 1, "c": 3}
+	for _, f := range []*Format{MsgPack, CBOR} {
+		first, err := f.Marshal(m)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for i := 0; i < 10; i++ {
+			b, _ := f.Marshal(m)
+			assert.Equal(t, first, b, f.Name)
+		}
+	}
+	b, _ := MarshalMsgPack(m)
+	assert.Equal(t, "\x83\xa1a\x01\xa1b\x02\xa1c\x03", string(b))
+	b, _ = MarshalCBOR(m)
+	assert.Equal(t, "\xa3\x61a\x01\x61b\x02\x61c\x03", string(b))
+}
+
+func TestMarshalCSV(t *testing.T) {
+	b, err := MarshalCSV(table{{"plain"}, {"=1+2"}, {"+1"}, {"-1"}, {"@SUM(A1)"}, {"\tx"}, {"\rx"}, {"a=b"}, {""}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "name\nplain\n'=1+2\n'+1\n'-1\n'@SUM(A1)\n'\tx\n\"'\rx\"\na=b\n\n", string(b))
+	}
+	_, err = MarshalCSV(&record{})
+	assert.Equal(t, ErrNotTable, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		accept string
+		v      interface{}
+		want   *Format
+	}{
+		{"", nil, JSON},
+		{"*/*", nil, JSON},
+		{"application/msgpack", nil, MsgPack},
+		{"application/vnd.msgpack", nil, MsgPack},
+		{"application/vnd.starter.v2+cbor", nil, CBOR},
+		{"application/json;q=0.5, application/cbor", nil, CBOR},
+		{"application/cbor;q=0.5, application/*", nil, JSON},
+		{"text/csv", table{}, CSV},
+		{"text/csv", nil, nil},
+		{"text/csv, application/json;q=0.1", nil, JSON},
+		{"text/html", nil, nil},
+	} {
+		assert.Equal(t, tc.want, Negotiate(tc.accept, tc.v), tc.accept)
+	}
+}
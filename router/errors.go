@@ -0,0 +1,21 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/logging"
+	"golang-starter-pack/utils"
+)
+
+// HTTPErrorHandler renders every error that reaches Echo, such as unknown
+// routes, bind failures or errors returned by handlers, in the same shape
+// handlers use for their own error responses.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	if err := utils.RenderError(c, http.StatusInternalServerError, err); err != nil {
+		logging.FromContext(c.Request().Context()).Error("rendering error", "err", err)
+	}
+}
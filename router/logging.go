@@ -0,0 +1,93 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"golang-starter-pack/logging"
+	"golang-starter-pack/router/middleware"
+)
+
+// RequestIDHeader carries the request ID. A well-formed ID sent by a proxy
+// or client is kept so their logs line up with ours.
+const RequestIDHeader = "X-Request-Id"
+
+// Probe routes are polled by load balancers every few seconds, so their
+// successful requests are left out of access logs.
+const (
+	HealthzPath = "/healthz"
+	ReadyzPath  = "/readyz"
+	VersionPath = "/version"
+)
+
+func isProbe(path string) bool {
+	return path == HealthzPath || path == ReadyzPath || path == VersionPath
+}
+
+// requestLogging assigns the request ID, starts the request's log fields
+// and writes one access line per request once it has been handled.
+func requestLogging(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		req := c.Request()
+		id := req.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Response().Header().Set(RequestIDHeader, id)
+		fields := []interface{}{"request_id", id}
+		if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+			fields = append(fields, "trace_id", sc.TraceID().String())
+		}
+		ctx := logging.NewContext(req.Context(), fields...)
+		c.SetRequest(req.WithContext(ctx))
+
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+		res := c.Response()
+		if isProbe(c.Path()) && res.Status < 500 {
+			return nil
+		}
+		kv := []interface{}{
+			"method", req.Method,
+			"route", c.Path(),
+			"path", req.URL.Path,
+			"status", res.Status,
+			"bytes", res.Size,
+			"duration_ms", logging.Millis(time.Since(start)),
+			"remote_ip", middleware.ClientIP(c),
+		}
+		l := logging.FromContext(ctx)
+		switch {
+		case res.Status >= 500:
+			l.Error("request", kv...)
+		case res.Status >= 400:
+			l.Warn("request", kv...)
+		default:
+			l.Info("request", kv...)
+		}
+		return nil
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/utils"
+)
+
+const (
+	// SessionCookie carries the session token for browser clients. It is
+	// HttpOnly so scripts cannot read it.
+	SessionCookie = "session"
+	// CSRFCookie holds the double-submit token. Scripts read it and echo
+	// it back in CSRFHeader on unsafe requests.
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+var ErrCSRFInvalid = utils.ErrCSRFInvalid()
+
+// FromHeader returns a TokenExtractor that reads "<scheme> <token>" from
+// header, e.g. FromHeader("Authorization", "Bearer").
+func FromHeader(header string, authScheme string) TokenExtractor {
+	return func(c echo.Context) (string, error) {
+		auth := c.Request().Header.Get(header)
+		l := len(authScheme)
+		if len(auth) > l+1 && auth[:l] == authScheme {
+			return auth[l+1:], nil
+		}
+		return "", ErrJWTMissing
+	}
+}
+
+// FromCookie returns a TokenExtractor that reads the token from the named
+// cookie. Because browsers attach cookies to cross-site requests, unsafe
+// methods must also pass the double-submit check: the CSRFHeader value has
+// to match the CSRFCookie value.
+func FromCookie(name string) TokenExtractor {
+	return func(c echo.Context) (string, error) {
+		cookie, err := c.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", ErrJWTMissing
+		}
+		if !isSafeMethod(c.Request().Method) {
+			csrf, err := c.Cookie(CSRFCookie)
+			header := c.Request().Header.Get(CSRFHeader)
+			if err != nil || csrf.Value == "" || subtle.ConstantTimeCompare([]byte(csrf.Value), []byte(header)) != 1 {
+				return "", ErrCSRFInvalid
+			}
+		}
+		return cookie.Value, nil
+	}
+}
+
+// FromQuery returns a TokenExtractor that reads the token from a query
+// parameter. Browsers cannot set headers on WebSocket handshakes, so it is
+// only honoured for upgrade requests to keep tokens out of ordinary URLs.
+func FromQuery(param string) TokenExtractor {
+	return func(c echo.Context) (string, error) {
+		if !strings.EqualFold(c.Request().Header.Get(echo.HeaderUpgrade), "websocket") {
+			return "", ErrJWTMissing
+		}
+		token := c.QueryParam(param)
+		if token == "" {
+			return "", ErrJWTMissing
+		}
+		return token, nil
+	}
+}
+
+// SetSessionCookies issues the HttpOnly session cookie read by FromCookie
+// together with a fresh CSRF token.
+func SetSessionCookies(c echo.Context, token string, ttl time.Duration) {
+	secure := c.IsTLS()
+	c.SetCookie(&http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	b := make([]byte, 16)
+	rand.Read(b)
+	c.SetCookie(&http.Cookie{
+		Name:     CSRFCookie,
+		Value:    hex.EncodeToString(b),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func ClearSessionCookies(c echo.Context) {
+	for _, name := range []string{SessionCookie, CSRFCookie} {
+		c.SetCookie(&http.Cookie{Name: name, Path: "/", MaxAge: -1})
+	}
+}
+
+func isSafeMethod(m string) bool {
+	return m == http.MethodGet || m == http.MethodHead || m == http.MethodOptions
+}
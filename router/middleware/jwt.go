@@ -6,21 +6,42 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo/v4"
+	"golang-starter-pack/logging"
 	"golang-starter-pack/utils"
 )
 
 type (
 	JWTConfig struct {
-		Skipper    Skipper
+		Skipper Skipper
+		// SigningKey is an HMAC secret. It is ignored when KeySet is set.
 		SigningKey interface{}
+		// KeySet verifies tokens against the key named by their kid header,
+		// allowing RS256 and EdDSA keys and rotation without logouts.
+		KeySet *utils.KeySet
+		// Issuer and Audience, when set, must match the iss and aud claims.
+		Issuer   string
+		Audience string
+		// Extractors are tried in order to find the token. Defaults to
+		// FromHeader("Authorization", "Token").
+		Extractors []TokenExtractor
+		// APIKeyValidator, when set, also authenticates requests carrying
+		// "Authorization: ApiKey <key>". It returns the owning player and
+		// the scopes granted to the key, or ErrAPIKeyInvalid for unknown
+		// and revoked keys; any other error is answered as a server error.
+		APIKeyValidator APIKeyValidator
 	}
-	Skipper      func(c echo.Context) bool
-	jwtExtractor func(echo.Context) (string, error)
+	APIKeyValidator func(key string) (playerID uint, scopes []string, err error)
+	Skipper         func(c echo.Context) bool
+	// TokenExtractor returns the raw token from a request, ErrJWTMissing
+	// when the request does not carry one in that location, or any other
+	// error to reject the request outright.
+	TokenExtractor func(echo.Context) (string, error)
 )
 
 var (
-	ErrJWTMissing = echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
-	ErrJWTInvalid = echo.NewHTTPError(http.StatusForbidden, "invalid or expired jwt")
+	ErrJWTMissing    = utils.ErrJWTMissing()
+	ErrJWTInvalid    = utils.ErrJWTInvalid()
+	ErrAPIKeyInvalid = utils.ErrAPIKeyInvalid()
 )
 
 func JWT(key interface{}) echo.MiddlewareFunc {
@@ -30,45 +51,86 @@ func JWT(key interface{}) echo.MiddlewareFunc {
 }
 
 func JWTWithConfig(config JWTConfig) echo.MiddlewareFunc {
-	extractor := jwtFromHeader("Authorization", "Token")
+	extractors := config.Extractors
+	if len(extractors) == 0 {
+		extractors = []TokenExtractor{FromHeader("Authorization", "Token")}
+	}
+	apiKeyExtractor := FromHeader("Authorization", "ApiKey")
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if config.KeySet != nil {
+			return config.KeySet.Keyfunc(token)
+		}
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return config.SigningKey, nil
+	}
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			auth, err := extractor(c)
+			if config.APIKeyValidator != nil {
+				if key, err := apiKeyExtractor(c); err == nil {
+					playerID, scopes, err := config.APIKeyValidator(key)
+					if err == ErrAPIKeyInvalid {
+						return utils.RenderError(c, http.StatusForbidden, err)
+					}
+					if err != nil {
+						return utils.RenderError(c, http.StatusInternalServerError, err)
+					}
+					c.Set("player", playerID)
+					c.Set("scopes", scopes)
+					logging.AddFields(c.Request().Context(), "player_id", playerID)
+					return next(c)
+				}
+			}
+			auth, err := extractToken(c, extractors)
+			if err != nil && err != ErrJWTMissing {
+				return utils.RenderError(c, http.StatusForbidden, err)
+			}
 			if err != nil {
 				if config.Skipper != nil {
 					if config.Skipper(c) {
 						return next(c)
 					}
 				}
-				return c.JSON(http.StatusUnauthorized, utils.NewError(err))
+				return utils.RenderError(c, http.StatusUnauthorized, err)
 			}
-			token, err := jwt.Parse(auth, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return config.SigningKey, nil
-			})
+			token, err := jwt.Parse(auth, keyFunc)
 			if err != nil {
-				return c.JSON(http.StatusForbidden, utils.NewError(ErrJWTInvalid))
+				return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
 			}
 			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-				playerID := uint(claims["id"].(float64))
+				// Purpose-bound tokens such as 2FA challenges are not sessions.
+				if _, ok := claims["purpose"]; ok {
+					return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
+				}
+				if config.Issuer != "" && !claims.VerifyIssuer(config.Issuer, true) {
+					return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
+				}
+				if config.Audience != "" && !claims.VerifyAudience(config.Audience, true) {
+					return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
+				}
+				playerID, err := utils.PlayerIDFromClaims(claims)
+				if err != nil {
+					return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
+				}
 				c.Set("player", playerID)
+				logging.AddFields(c.Request().Context(), "player_id", playerID)
 				return next(c)
 			}
-			return c.JSON(http.StatusForbidden, utils.NewError(ErrJWTInvalid))
+			return utils.RenderError(c, http.StatusForbidden, ErrJWTInvalid)
 		}
 	}
 }
 
-// jwtFromHeader returns a `jwtExtractor` that extracts token from the request header.
-func jwtFromHeader(header string, authScheme string) jwtExtractor {
-	return func(c echo.Context) (string, error) {
-		auth := c.Request().Header.Get(header)
-		l := len(authScheme)
-		if len(auth) > l+1 && auth[:l] == authScheme {
-			return auth[l+1:], nil
+func extractToken(c echo.Context, extractors []TokenExtractor) (string, error) {
+	for _, extract := range extractors {
+		token, err := extract(c)
+		if err == nil {
+			return token, nil
+		}
+		if err != ErrJWTMissing {
+			return "", err
 		}
-		return "", ErrJWTMissing
 	}
+	return "", ErrJWTMissing
 }
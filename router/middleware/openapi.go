@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/openapi"
+	"golang-starter-pack/utils"
+)
+
+type OpenAPIConfig struct {
+	Skipper  Skipper
+	Document *openapi.Document
+	// Prefix is stripped from routes before they are looked up in the
+	// document, which lists paths relative to its server URL.
+	Prefix string
+	// AllowQuery lists query parameters accepted on every route although
+	// the document does not describe them, such as access_token.
+	AllowQuery []string
+}
+
+// Violations are reported in the error's fields, keyed by JSON pointers
+// into the body under /body and into the query parameters under /query.
+const (
+	bodyPointer  = "/body"
+	queryPointer = "/query"
+)
+
+var (
+	ErrMalformedJSON = utils.ErrMalformedJSON()
+	routeParam       = regexp.MustCompile(`:([A-Za-z]+)`)
+)
+
+// OpenAPIWithConfig rejects requests whose JSON body or query parameters
+// do not match the document, including members and parameters it does not
+// describe. Routes missing from the document are passed through.
+func OpenAPIWithConfig(config OpenAPIConfig) echo.MiddlewareFunc {
+	allowed := make(map[string]bool, len(config.AllowQuery))
+	for _, q := range config.AllowQuery {
+		allowed[q] = true
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper != nil && config.Skipper(c) {
+				return next(c)
+			}
+			path := routeParam.ReplaceAllString(strings.TrimPrefix(c.Path(), config.Prefix), "{$1}")
+			op := config.Document.Operation(c.Request().Method, path)
+			if op == nil {
+				return next(c)
+			}
+			violations := validateQuery(config.Document, op, c.QueryParams(), allowed)
+			body, err := validateBody(config.Document, op, c.Request())
+			if err != nil {
+				return utils.RenderError(c, http.StatusBadRequest, err)
+			}
+			violations = append(violations, body...)
+			if len(violations) > 0 {
+				return utils.RenderError(c, http.StatusUnprocessableEntity, violationError(violations))
+			}
+			return next(c)
+		}
+	}
+}
+
+func validateQuery(doc *openapi.Document, op *openapi.Operation, query map[string][]string, allowed map[string]bool) []openapi.Violation {
+	params := make(map[string]*openapi.Parameter)
+	for _, p := range op.Parameters {
+		if p.In == "query" {
+			params[p.Name] = p
+		}
+	}
+	var out []openapi.Violation
+	for _, name := range sortedKeys(query) {
+		ptr := queryPointer + "/" + name
+		p, ok := params[name]
+		switch {
+		case allowed[name]:
+		case !ok:
+			out = append(out, openapi.Violation{Pointer: ptr, Rule: "additionalProperties"})
+		default:
+			for _, raw := range query[name] {
+				out = append(out, doc.Validate(p.Schema, openapi.ParseParam(p.Schema, raw), ptr)...)
+			}
+		}
+	}
+	for _, p := range op.Parameters {
+		if _, ok := query[p.Name]; p.In == "query" && p.Required && !ok {
+			out = append(out, openapi.Violation{Pointer: queryPointer + "/" + p.Name, Rule: "required"})
+		}
+	}
+	return out
+}
+
+// validateBody checks JSON bodies and puts the body back for binding.
+// Other media types, such as patches, are left to their handlers.
+func validateBody(doc *openapi.Document, op *openapi.Operation, req *http.Request) ([]openapi.Violation, error) {
+	if op.RequestBody == nil {
+		return nil, nil
+	}
+	mt, ok := op.RequestBody.Content[echo.MIMEApplicationJSON]
+	if !ok {
+		return nil, nil
+	}
+	ctype, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if ctype != echo.MIMEApplicationJSON {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if len(bytes.TrimSpace(b)) == 0 {
+		if op.RequestBody.Required {
+			return []openapi.Violation{{Pointer: bodyPointer, Rule: "required"}}, nil
+		}
+		return nil, nil
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, ErrMalformedJSON
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, ErrMalformedJSON
+	}
+	return doc.Validate(mt.Schema, v, bodyPointer), nil
+}
+
+// violationError reports each violation under its pointer, keeping the
+// first when several hit the same value.
+func violationError(violations []openapi.Violation) *utils.AppError {
+	fields := make(map[string]utils.FieldError, len(violations))
+	for _, v := range violations {
+		if _, ok := fields[v.Pointer]; ok {
+			continue
+		}
+		code, key := violationMessage(v)
+		fields[v.Pointer] = utils.NewFieldError(code, key, map[string]string{"param": v.Param})
+	}
+	return utils.NewValidation(fields)
+}
+
+// violationMessage maps schema keywords to the codes and messages used for
+// the same rules by the struct validator, so clients see one vocabulary.
+func violationMessage(v openapi.Violation) (code, key string) {
+	switch v.Rule {
+	case "required":
+		return "required", "validation.required"
+	case "additionalProperties":
+		return "unknown", "validation.unknown"
+	case "type":
+		return "type", "validation.type"
+	case "enum":
+		return "oneof", "validation.oneof"
+	case "minLength":
+		return "min", "validation.min"
+	case "maxLength":
+		return "max", "validation.max"
+	case "minItems":
+		return "min", "validation.min_items"
+	case "maxItems":
+		return "max", "validation.max_items"
+	case "minimum":
+		return "minimum", "validation.minimum"
+	case "maximum":
+		return "maximum", "validation.maximum"
+	case "format":
+		switch v.Param {
+		case "email":
+			return "email", "validation.email"
+		case "uri":
+			return "url", "validation.url"
+		}
+	}
+	return "invalid", "validation.invalid"
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/logging"
+	"golang-starter-pack/utils"
+)
+
+type (
+	// RateLimitPolicy is a token bucket: it holds up to Burst requests and
+	// refills at Rate requests per second.
+	RateLimitPolicy struct {
+		Rate  float64
+		Burst int
+	}
+
+	// RateLimitResult is the outcome of taking a token from a bucket.
+	RateLimitResult struct {
+		Allowed   bool
+		Remaining int
+		// RetryAfter is how long until a token is available when the
+		// request was refused.
+		RetryAfter time.Duration
+		// Reset is how long until the bucket is full again.
+		Reset time.Duration
+	}
+
+	// RateLimitStore keeps the buckets. Implementations backed by a shared
+	// store let several instances enforce one limit.
+	RateLimitStore interface {
+		Take(key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error)
+	}
+
+	RateLimitConfig struct {
+		Skipper Skipper
+		// Name separates the buckets of different policies for one client.
+		Name   string
+		Policy RateLimitPolicy
+		Store  RateLimitStore
+		// KeyFunc identifies the client. Defaults to RateLimitKey.
+		KeyFunc func(echo.Context) string
+	}
+)
+
+// RateLimitKey keys requests by the authenticated player, falling back to
+// the client IP as ClientIP sees it, so that anonymous clients cannot pick
+// a fresh bucket with a forged X-Forwarded-For. It must run after the JWT
+// middleware to see the player.
+func RateLimitKey(c echo.Context) string {
+	if id, ok := c.Get("player").(uint); ok && id != 0 {
+		return "player:" + strconv.FormatUint(uint64(id), 10)
+	}
+	return "ip:" + ClientIP(c)
+}
+
+func RateLimitWithConfig(config RateLimitConfig) echo.MiddlewareFunc {
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitKey
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper != nil && config.Skipper(c) {
+				return next(c)
+			}
+			key := config.Name + ":" + config.KeyFunc(c)
+			res, err := config.Store.Take(key, config.Policy, time.Now())
+			if err != nil {
+				// A limiter outage should not take the API down with it.
+				logging.FromContext(c.Request().Context()).Warn("rate limit store failed", "limit", config.Name, "err", err)
+				return next(c)
+			}
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(config.Policy.Burst))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("X-RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+				return utils.RenderError(c, http.StatusTooManyRequests, utils.ErrRateLimited())
+			}
+			return next(c)
+		}
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimitStore keeps buckets in process memory. Buckets that have
+// refilled completely are dropped periodically.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, p RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.sweep(now)
+	}
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(p.Burst), b.tokens+now.Sub(b.last).Seconds()*p.Rate)
+	b.last = now
+
+	var res RateLimitResult
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = refill(1-b.tokens, p.Rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = refill(float64(p.Burst)-b.tokens, p.Rate)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+	s.lastSweep = now
+}
+
+// refill returns how long it takes to regain tokens at rate per second.
+func refill(tokens, rate float64) time.Duration {
+	if tokens <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/render"
+)
+
+// renderContext renders JSON in the format the request accepts.
+type renderContext struct {
+	echo.Context
+}
+
+// Render makes handlers' c.JSON answer in the format the Accept header
+// prefers: JSON, MessagePack, CBOR or, for responses that are a
+// render.Table, CSV. Requests accepting none of them get JSON. Install it
+// before APIVersionWithConfig so that versions are mapped first.
+func Render() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			varyAccept(c.Response().Header())
+			return next(&renderContext{c})
+		}
+	}
+}
+
+func (c *renderContext) JSON(code int, i interface{}) error {
+	f := render.Negotiate(c.Request().Header.Get(echo.HeaderAccept), i)
+	if f == nil || f == render.JSON {
+		c.Response().Header().Set(echo.HeaderContentType, render.JSON.ContentType)
+		return c.Context.JSON(code, i)
+	}
+	b, err := f.Marshal(i)
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, f.ContentType, b)
+}
+
+// varyAccept adds Accept to the Vary header unless it is already there.
+func varyAccept(h http.Header) {
+	for _, v := range h[echo.HeaderVary] {
+		for _, name := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), echo.HeaderAccept) {
+				return
+			}
+		}
+	}
+	h.Add(echo.HeaderVary, echo.HeaderAccept)
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang-starter-pack/utils"
+)
+
+type (
+	// APIVersion describes one version of the API. Deprecated and Sunset
+	// are zero while the version is current.
+	APIVersion struct {
+		Number     int
+		Deprecated time.Time
+		Sunset     time.Time
+	}
+
+	APIVersionConfig struct {
+		// Prefix is where the API is mounted, e.g. "/api".
+		Prefix   string
+		Versions []APIVersion
+		// Default serves requests that name no version.
+		Default int
+		// MediaType is the vendor media type naming a version in Accept,
+		// with %d for its number, e.g. "application/vnd.starter.v%d+json".
+		MediaType string
+		// Map reshapes a value rendered with c.JSON for a version. It is
+		// the only difference between versions: requests, and responses
+		// written with Blob, Stream or NoContent, are left alone.
+		Map func(version int, v interface{}) interface{}
+	}
+
+	// versionContext renders JSON the way its API version expects.
+	versionContext struct {
+		echo.Context
+		version int
+		mapper  func(int, interface{}) interface{}
+	}
+)
+
+// APIVersionHeader reports the version that served a response.
+const APIVersionHeader = "Api-Version"
+
+var (
+	ErrAPIVersionUnknown       = utils.ErrAPIVersionUnknown()
+	ErrAPIVersionNotAcceptable = utils.ErrAPIVersionNotAcceptable()
+)
+
+// StripAPIVersion routes /prefix/vN/... as /prefix/..., recording N for
+// APIVersionWithConfig. Install it with Echo.Pre, as routing comes after.
+func StripAPIVersion(prefix string) echo.MiddlewareFunc {
+	versioned := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + `/v([0-9]+)(/|$)`)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if m := versioned.FindStringSubmatch(req.URL.Path); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				c.Set("apiVersion", n)
+				strip := len(prefix) + 2 + len(m[1])
+				req.URL.Path = prefix + req.URL.Path[strip:]
+				req.URL.RawPath = ""
+			}
+			return next(c)
+		}
+	}
+}
+
+// APIVersionWithConfig picks the version of a request from its path, via
+// StripAPIVersion, or else from the Accept header, and makes handlers'
+// c.JSON render through config.Map for it; versions cannot change what a
+// route accepts. Deprecated versions answer with Deprecation, Sunset and
+// successor Link headers.
+func APIVersionWithConfig(config APIVersionConfig) echo.MiddlewareFunc {
+	versions := make(map[int]APIVersion, len(config.Versions))
+	latest := config.Default
+	for _, v := range config.Versions {
+		versions[v.Number] = v
+		if v.Number > latest {
+			latest = v.Number
+		}
+	}
+	vendor := strings.SplitN(config.MediaType, "%d", 2)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response().Header()
+			n, ok := c.Get("apiVersion").(int)
+			if ok {
+				if _, known := versions[n]; !known {
+					return utils.RenderError(c, http.StatusNotFound, ErrAPIVersionUnknown)
+				}
+			} else {
+				varyAccept(res)
+				n = config.Default
+				if requested, ok := acceptedVersion(c.Request().Header.Get(echo.HeaderAccept), vendor); ok {
+					if _, known := versions[requested]; !known {
+						return utils.RenderError(c, http.StatusNotAcceptable, ErrAPIVersionNotAcceptable)
+					}
+					n = requested
+				}
+				c.Set("apiVersion", n)
+			}
+			v := versions[n]
+			res.Set(APIVersionHeader, strconv.Itoa(n))
+			if !v.Deprecated.IsZero() {
+				res.Set("Deprecation", "@"+strconv.FormatInt(v.Deprecated.Unix(), 10))
+				if n != latest {
+					res.Add("Link", "<"+config.Prefix+"/v"+strconv.Itoa(latest)+`>; rel="successor-version"`)
+				}
+			}
+			if !v.Sunset.IsZero() {
+				res.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if config.Map == nil {
+				return next(c)
+			}
+			return next(&versionContext{Context: c, version: n, mapper: config.Map})
+		}
+	}
+}
+
+// acceptedVersion finds the vendor media type in an Accept header.
+func acceptedVersion(accept string, vendor []string) (int, bool) {
+	if len(vendor) != 2 {
+		return 0, false
+	}
+	for _, part := range strings.Split(accept, ",") {
+		t := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if strings.HasPrefix(t, vendor[0]) && strings.HasSuffix(t, vendor[1]) && len(t) > len(vendor[0])+len(vendor[1]) {
+			if n, err := strconv.Atoi(t[len(vendor[0]) : len(t)-len(vendor[1])]); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// APIVersionOf returns the version serving a request, 0 outside the API.
+func APIVersionOf(c echo.Context) int {
+	n, _ := c.Get("apiVersion").(int)
+	return n
+}
+
+func (c *versionContext) JSON(code int, i interface{}) error {
+	return c.Context.JSON(code, c.mapper(c.version, i))
+}
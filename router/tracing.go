@@ -0,0 +1,40 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader returns the request's trace ID to clients, for quoting in
+// bug reports.
+const TraceIDHeader = "X-Trace-Id"
+
+// TraceparentHeader is the W3C Trace Context header continued by requests.
+const TraceparentHeader = "traceparent"
+
+// serviceName is the server name otelecho records when a request has no
+// Host.
+const serviceName = "golang-starter-pack"
+
+var tracePropagator = propagation.TraceContext{}
+
+// requestTracing starts an OpenTelemetry server span per request with
+// otelecho, continuing the trace of an incoming traceparent header, and
+// returns the trace to the client. The span is stored in the request
+// context for handlers and stores to build on.
+func requestTracing() echo.MiddlewareFunc {
+	traced := otelecho.Middleware(serviceName, otelecho.WithPropagators(tracePropagator))
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return traced(func(c echo.Context) error {
+			ctx := c.Request().Context()
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				h := c.Response().Header()
+				h.Set(TraceIDHeader, sc.TraceID().String())
+				tracePropagator.Inject(ctx, propagation.HeaderCarrier(h))
+			}
+			return next(c)
+		})
+	}
+}
@@ -1,10 +1,49 @@
 package router
 
-import "gopkg.in/go-playground/validator.v9"
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang-starter-pack/openapi"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+var (
+	usernameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	tagRegexp      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	// reservedUsernames would collide with routes or impersonate staff.
+	reservedUsernames = map[string]bool{
+		"admin": true, "administrator": true, "root": true, "system": true,
+		"support": true, "api": true, "me": true, "player": true,
+		"players": true, "profiles": true, "items": true, "tags": true,
+	}
+)
+
+const (
+	minPasswordLength = 8
+	maxTagLength      = 32
+)
 
 func NewValidator() *Validator {
+	v := validator.New()
+	// Report fields by their JSON name so errors match the request body.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("username", validateUsername)
+	v.RegisterValidation("notreserved", validateNotReserved)
+	v.RegisterValidation("password", validatePassword)
+	v.RegisterValidation("tag", validateTag)
 	return &Validator{
-		validator: validator.New(),
+		validator: v,
 	}
 }
 
@@ -15,3 +54,60 @@ type Validator struct {
 func (v *Validator) Validate(i interface{}) error {
 	return v.validator.Struct(i)
 }
+
+// DescribeValidation documents the custom validate tags registered by
+// NewValidator in OpenAPI schemas.
+func DescribeValidation(tag, _ string, s *openapi.Schema) bool {
+	switch tag {
+	case "username":
+		s.Pattern = usernameRegexp.String()
+	case "notreserved":
+		s.Description = "Must not be a reserved name such as admin or api."
+	case "password":
+		n := minPasswordLength
+		s.MinLength = &n
+		s.Description = "At least " + strconv.Itoa(n) + " characters mixing letters and digits."
+	case "tag":
+		n := maxTagLength
+		s.MaxLength = &n
+		s.Pattern = tagRegexp.String()
+	default:
+		return false
+	}
+	return true
+}
+
+// validateUsername allows letters, digits, "_" and "-". Length is left to
+// min/max tags.
+func validateUsername(fl validator.FieldLevel) bool {
+	return usernameRegexp.MatchString(fl.Field().String())
+}
+
+func validateNotReserved(fl validator.FieldLevel) bool {
+	return !reservedUsernames[strings.ToLower(fl.Field().String())]
+}
+
+// validatePassword requires at least minPasswordLength characters mixing
+// letters and digits.
+func validatePassword(fl validator.FieldLevel) bool {
+	p := fl.Field().String()
+	if len(p) < minPasswordLength {
+		return false
+	}
+	var letter, digit bool
+	for _, r := range p {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return letter && digit
+}
+
+// validateTag accepts lowercase words joined by single dashes.
+func validateTag(fl validator.FieldLevel) bool {
+	t := fl.Field().String()
+	return len(t) <= maxTagLength && tagRegexp.MatchString(t)
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang-starter-pack/cache"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+)
+
+// CachedItemStore serves item.Store reads from a cache. Every item write
+// moves the store to a new key generation, so no entry written before the
+// change is read again. Author profiles embedded in cached items may lag
+// behind player updates by up to the TTL.
+type CachedItemStore struct {
+	item.Store
+	cache cache.Cache
+	ttl   time.Duration
+	stats *cache.Stats
+}
+
+const itemGenerationKey = "items:gen"
+
+func NewCachedItemStore(s item.Store, c cache.Cache, ttl time.Duration) *CachedItemStore {
+	return &CachedItemStore{
+		Store: s,
+		cache: c,
+		ttl:   ttl,
+		stats: new(cache.Stats),
+	}
+}
+
+// WithContext binds the underlying store to ctx for tracing, sharing the
+// cache and its statistics.
+func (cs *CachedItemStore) WithContext(ctx context.Context) item.Store {
+	c := *cs
+	if s, ok := cs.Store.(interface {
+		WithContext(context.Context) item.Store
+	}); ok {
+		c.Store = s.WithContext(ctx)
+	}
+	return &c
+}
+
+// Stats reports the hit and miss counts of cached reads.
+func (cs *CachedItemStore) Stats() *cache.Stats {
+	return cs.stats
+}
+
+// Cached values are wrapped in structs because gob cannot encode a nil
+// pointer or slice on its own.
+type (
+	cachedItem struct {
+		Item *model.Item
+	}
+	cachedList struct {
+		Items []model.Item
+		Count int
+	}
+	cachedTags struct {
+		Tags []model.Tag
+	}
+)
+
+func (cs *CachedItemStore) GetBySlug(s string) (*model.Item, error) {
+	var m cachedItem
+	err := cs.cached("slug:"+s, &m, func() (interface{}, error) {
+		a, err := cs.Store.GetBySlug(s)
+		return cachedItem{Item: a}, err
+	})
+	return m.Item, err
+}
+
+func (cs *CachedItemStore) List(order item.Order, offset, limit int) ([]model.Item, int, error) {
+	return cs.list(fmt.Sprintf("list:%s:%d:%d", order, offset, limit), func() ([]model.Item, int, error) {
+		return cs.Store.List(order, offset, limit)
+	})
+}
+
+func (cs *CachedItemStore) ListByTag(tag string, order item.Order, offset, limit int) ([]model.Item, int, error) {
+	return cs.list(fmt.Sprintf("tag:%s:%d:%d:%s", order, offset, limit, tag), func() ([]model.Item, int, error) {
+		return cs.Store.ListByTag(tag, order, offset, limit)
+	})
+}
+
+func (cs *CachedItemStore) ListByAuthor(username string, order item.Order, offset, limit int) ([]model.Item, int, error) {
+	return cs.list(fmt.Sprintf("author:%s:%d:%d:%s", order, offset, limit, username), func() ([]model.Item, int, error) {
+		return cs.Store.ListByAuthor(username, order, offset, limit)
+	})
+}
+
+func (cs *CachedItemStore) ListByWhoFavorited(username string, order item.Order, offset, limit int) ([]model.Item, int, error) {
+	return cs.list(fmt.Sprintf("favorited:%s:%d:%d:%s", order, offset, limit, username), func() ([]model.Item, int, error) {
+		return cs.Store.ListByWhoFavorited(username, order, offset, limit)
+	})
+}
+
+func (cs *CachedItemStore) ListTags() ([]model.Tag, error) {
+	var t cachedTags
+	err := cs.cached("tags", &t, func() (interface{}, error) {
+		tags, err := cs.Store.ListTags()
+		return cachedTags{Tags: tags}, err
+	})
+	return t.Tags, err
+}
+
+func (cs *CachedItemStore) CreateItem(a *model.Item) error {
+	defer cs.invalidate()
+	return cs.Store.CreateItem(a)
+}
+
+func (cs *CachedItemStore) UpdateItem(a *model.Item, tagList []string) error {
+	defer cs.invalidate()
+	return cs.Store.UpdateItem(a, tagList)
+}
+
+func (cs *CachedItemStore) DeleteItem(a *model.Item) error {
+	defer cs.invalidate()
+	return cs.Store.DeleteItem(a)
+}
+
+func (cs *CachedItemStore) AddComment(a *model.Item, c *model.Comment) error {
+	defer cs.invalidate()
+	return cs.Store.AddComment(a, c)
+}
+
+func (cs *CachedItemStore) DeleteComment(c *model.Comment) error {
+	defer cs.invalidate()
+	return cs.Store.DeleteComment(c)
+}
+
+func (cs *CachedItemStore) AddFavorite(a *model.Item, playerID uint) error {
+	defer cs.invalidate()
+	return cs.Store.AddFavorite(a, playerID)
+}
+
+func (cs *CachedItemStore) RemoveFavorite(a *model.Item, playerID uint) error {
+	defer cs.invalidate()
+	return cs.Store.RemoveFavorite(a, playerID)
+}
+
+func (cs *CachedItemStore) list(key string, load func() ([]model.Item, int, error)) ([]model.Item, int, error) {
+	var l cachedList
+	err := cs.cached(key, &l, func() (interface{}, error) {
+		items, count, err := load()
+		return cachedList{Items: items, Count: count}, err
+	})
+	return l.Items, l.Count, err
+}
+
+// cached decodes the entry for key into dst, or calls load, stores its
+// result and decodes that instead. Values go through gob either way, so
+// callers never share an instance. Cache failures fall back to load.
+func (cs *CachedItemStore) cached(key string, dst interface{}, load func() (interface{}, error)) error {
+	key = "items:" + cs.generation() + ":" + key
+	if b, ok, err := cs.cache.Get(key); err == nil && ok {
+		if gob.NewDecoder(bytes.NewReader(b)).Decode(dst) == nil {
+			cs.stats.Hit()
+			return nil
+		}
+	}
+	cs.stats.Miss()
+	v, err := load()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	cs.cache.Set(key, buf.Bytes(), cs.ttl)
+	return gob.NewDecoder(&buf).Decode(dst)
+}
+
+// generation returns the current key generation, starting a new one when
+// the marker is missing, e.g. after eviction, so old entries stay unread.
+func (cs *CachedItemStore) generation() string {
+	if b, ok, err := cs.cache.Get(itemGenerationKey); err == nil && ok {
+		return string(b)
+	}
+	return cs.invalidate()
+}
+
+func (cs *CachedItemStore) invalidate() string {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 36)
+	cs.cache.Set(itemGenerationKey, []byte(gen), 0)
+	return gen
+}
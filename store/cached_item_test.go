@@ -0,0 +1,71 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang-starter-pack/cache"
+	"golang-starter-pack/item"
+	"golang-starter-pack/model"
+)
+
+// loadCountingStore answers GetBySlug from a map and counts the calls
+// that reach it. Other reads are not used by these tests.
+type loadCountingStore struct {
+	item.Store
+	items map[string]*model.Item
+	loads int
+}
+
+func (s *loadCountingStore) GetBySlug(slug string) (*model.Item, error) {
+	s.loads++
+	return s.items[slug], nil
+}
+
+func (s *loadCountingStore) CreateItem(a *model.Item) error {
+	s.items[a.Slug] = a
+	return nil
+}
+
+func TestCachedItemStoreGenerations(t *testing.T) {
+	backing := &loadCountingStore{items: map[string]*model.Item{"a": {Slug: "a", Title: "first"}}}
+	c := cache.NewLRU(100)
+	cs := NewCachedItemStore(backing, c, 0)
+	get := func(s *CachedItemStore, slug string) *model.Item {
+		a, err := s.GetBySlug(slug)
+		assert.NoError(t, err)
+		return a
+	}
+
+	assert.Equal(t, "first", get(cs, "a").Title)
+	assert.Equal(t, "first", get(cs, "a").Title)
+	assert.Equal(t, 1, backing.loads)
+	assert.Nil(t, get(cs, "missing"))
+	assert.Nil(t, get(cs, "missing"))
+	assert.Equal(t, 2, backing.loads)
+
+	// A write starts a new generation, leaving earlier entries unread.
+	entries := c.Len()
+	assert.NoError(t, cs.CreateItem(&model.Item{Slug: "missing", Title: "created"}))
+	assert.Equal(t, "created", get(cs, "missing").Title)
+	assert.Equal(t, "first", get(cs, "a").Title)
+	assert.Equal(t, 4, backing.loads)
+	assert.Equal(t, entries+2, c.Len())
+
+	// Stores sharing a cache share its generation, so a write through one
+	// invalidates the reads of the other.
+	other := NewCachedItemStore(backing, c, 0)
+	assert.Equal(t, "first", get(other, "a").Title)
+	assert.Equal(t, 4, backing.loads)
+	backing.items["a"].Title = "changed"
+	assert.NoError(t, other.CreateItem(&model.Item{Slug: "b"}))
+	assert.Equal(t, "changed", get(cs, "a").Title)
+	assert.Equal(t, 5, backing.loads)
+
+	// Losing the marker, e.g. to eviction, also starts a new generation.
+	assert.NoError(t, c.Delete(itemGenerationKey))
+	get(cs, "a")
+	assert.Equal(t, 6, backing.loads)
+	assert.Equal(t, uint64(2), cs.Stats().Hits())
+	assert.Equal(t, uint64(1), other.Stats().Hits())
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"strings"
+
+	"golang-starter-pack/utils"
+)
+
+const uniqueViolation = "UNIQUE constraint failed: "
+
+// translateError maps constraint violations reported by the driver to
+// domain errors, so raw SQL text never reaches clients.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if i := strings.Index(msg, uniqueViolation); i >= 0 {
+		// "UNIQUE constraint failed: players.username[, ...]"
+		column := strings.SplitN(msg[i+len(uniqueViolation):], ",", 2)[0]
+		column = column[strings.LastIndex(column, ".")+1:]
+		return utils.NewTaken(column)
+	}
+	return err
+}
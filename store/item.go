@@ -1,8 +1,12 @@
 package store
 
 import (
+	"context"
 	"github.com/jinzhu/gorm"
+	"golang-starter-pack/db"
+	"golang-starter-pack/item"
 	"golang-starter-pack/model"
+	"golang-starter-pack/utils"
 )
 
 type ItemStore struct {
@@ -15,9 +19,15 @@ func NewItemStore(db *gorm.DB) *ItemStore {
 	}
 }
 
+// WithContext returns a copy of the store whose SQL statements are traced
+// as children of the span in ctx.
+func (as *ItemStore) WithContext(ctx context.Context) item.Store {
+	return &ItemStore{db: db.WithContext(as.db, ctx)}
+}
+
 func (as *ItemStore) GetBySlug(s string) (*model.Item, error) {
 	var m model.Item
-	err := as.db.Where(&model.Item{Slug: s}).Preload("Favorites").Preload("Tags").Preload("Author").Find(&m).Error
+	err := as.db.Where(&model.Item{Slug: s}).Preload("Tags").Preload("Author").Find(&m).Error
 	if err != nil {
 		if gorm.IsRecordNotFoundError(err) {
 			return nil, nil
@@ -29,7 +39,7 @@ func (as *ItemStore) GetBySlug(s string) (*model.Item, error) {
 
 func (as *ItemStore) GetPlayerItemBySlug(playerID uint, slug string) (*model.Item, error) {
 	var m model.Item
-	err := as.db.Where(&model.Item{Slug: slug, AuthorID: playerID}).Find(&m).Error
+	err := as.db.Where(&model.Item{Slug: slug, AuthorID: playerID}).Preload("Tags").Preload("Author").Find(&m).Error
 	if err != nil {
 		if gorm.IsRecordNotFoundError(err) {
 			return nil, nil
@@ -43,7 +53,8 @@ func (as *ItemStore) CreateItem(a *model.Item) error {
 	tags := a.Tags
 	tx := as.db.Begin()
 	if err := tx.Create(&a).Error; err != nil {
-		return err
+		tx.Rollback()
+		return translateError(err)
 	}
 	for _, t := range a.Tags {
 		err := tx.Where(&model.Tag{Tag: t.Tag}).First(&t).Error
@@ -56,7 +67,7 @@ func (as *ItemStore) CreateItem(a *model.Item) error {
 			return err
 		}
 	}
-	if err := tx.Where(a.ID).Preload("Favorites").Preload("Tags").Preload("Author").Find(&a).Error; err != nil {
+	if err := tx.Where(a.ID).Preload("Tags").Preload("Author").Find(&a).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -66,8 +77,15 @@ func (as *ItemStore) CreateItem(a *model.Item) error {
 
 func (as *ItemStore) UpdateItem(a *model.Item, tagList []string) error {
 	tx := as.db.Begin()
-	if err := tx.Model(a).Update(a).Error; err != nil {
-		return err
+	// Only write if nobody updated the item since it was loaded.
+	res := tx.Model(a).Where("updated_at = ?", a.UpdatedAt).Omit("favorites_count", "comments_count").Update(a)
+	if res.Error != nil {
+		tx.Rollback()
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+		return utils.ErrModifiedConcurrently()
 	}
 	tags := make([]model.Tag, 0)
 	for _, t := range tagList {
@@ -83,7 +101,7 @@ func (as *ItemStore) UpdateItem(a *model.Item, tagList []string) error {
 		tx.Rollback()
 		return err
 	}
-	if err := tx.Where(a.ID).Preload("Favorites").Preload("Tags").Preload("Author").Find(a).Error; err != nil {
+	if err := tx.Where(a.ID).Preload("Tags").Preload("Author").Find(a).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -94,17 +112,17 @@ func (as *ItemStore) DeleteItem(a *model.Item) error {
 	return as.db.Delete(a).Error
 }
 
-func (as *ItemStore) List(offset, limit int) ([]model.Item, int, error) {
+func (as *ItemStore) List(order item.Order, offset, limit int) ([]model.Item, int, error) {
 	var (
 		items []model.Item
 		count int
 	)
 	as.db.Model(&items).Count(&count)
-	as.db.Preload("Favorites").Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order("created_at desc").Find(&items)
+	as.db.Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order(orderBy(order)).Find(&items)
 	return items, count, nil
 }
 
-func (as *ItemStore) ListByTag(tag string, offset, limit int) ([]model.Item, int, error) {
+func (as *ItemStore) ListByTag(tag string, order item.Order, offset, limit int) ([]model.Item, int, error) {
 	var (
 		t     model.Tag
 		items []model.Item
@@ -112,14 +130,17 @@ func (as *ItemStore) ListByTag(tag string, offset, limit int) ([]model.Item, int
 	)
 	err := as.db.Where(&model.Tag{Tag: tag}).First(&t).Error
 	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, 0, nil
+		}
 		return nil, 0, err
 	}
-	as.db.Model(&t).Preload("Favorites").Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order("created_at desc").Association("Items").Find(&items)
+	as.db.Model(&t).Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order(orderBy(order)).Association("Items").Find(&items)
 	count = as.db.Model(&t).Association("Items").Count()
 	return items, count, nil
 }
 
-func (as *ItemStore) ListByAuthor(username string, offset, limit int) ([]model.Item, int, error) {
+func (as *ItemStore) ListByAuthor(username string, order item.Order, offset, limit int) ([]model.Item, int, error) {
 	var (
 		u     model.Player
 		items []model.Item
@@ -127,15 +148,18 @@ func (as *ItemStore) ListByAuthor(username string, offset, limit int) ([]model.I
 	)
 	err := as.db.Where(&model.Player{Username: username}).First(&u).Error
 	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, 0, nil
+		}
 		return nil, 0, err
 	}
-	as.db.Where(&model.Item{AuthorID: u.ID}).Preload("Favorites").Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order("created_at desc").Find(&items)
+	as.db.Where(&model.Item{AuthorID: u.ID}).Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order(orderBy(order)).Find(&items)
 	as.db.Where(&model.Item{AuthorID: u.ID}).Model(&model.Item{}).Count(&count)
 
 	return items, count, nil
 }
 
-func (as *ItemStore) ListByWhoFavorited(username string, offset, limit int) ([]model.Item, int, error) {
+func (as *ItemStore) ListByWhoFavorited(username string, order item.Order, offset, limit int) ([]model.Item, int, error) {
 	var (
 		u     model.Player
 		items []model.Item
@@ -143,14 +167,17 @@ func (as *ItemStore) ListByWhoFavorited(username string, offset, limit int) ([]m
 	)
 	err := as.db.Where(&model.Player{Username: username}).First(&u).Error
 	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, 0, nil
+		}
 		return nil, 0, err
 	}
-	as.db.Model(&u).Preload("Favorites").Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order("created_at desc").Association("Favorites").Find(&items)
+	as.db.Model(&u).Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order(orderBy(order)).Association("Favorites").Find(&items)
 	count = as.db.Model(&u).Association("Favorites").Count()
 	return items, count, nil
 }
 
-func (as *ItemStore) ListFeed(playerID uint, offset, limit int) ([]model.Item, int, error) {
+func (as *ItemStore) ListFeed(playerID uint, order item.Order, offset, limit int) ([]model.Item, int, error) {
 	var (
 		u     model.Player
 		items []model.Item
@@ -166,14 +193,22 @@ func (as *ItemStore) ListFeed(playerID uint, offset, limit int) ([]model.Item, i
 	for _, i := range followings {
 		ids = append(ids, i.FollowingID)
 	}
-	as.db.Where("author_id in (?)", ids).Preload("Favorites").Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order("created_at desc").Find(&items)
+	as.db.Where("author_id in (?)", ids).Preload("Tags").Preload("Author").Offset(offset).Limit(limit).Order(orderBy(order)).Find(&items)
 	as.db.Where(&model.Item{AuthorID: u.ID}).Model(&model.Item{}).Count(&count)
 	return items, count, nil
 }
 
 func (as *ItemStore) AddComment(a *model.Item, c *model.Comment) error {
-	err := as.db.Model(a).Association("Comments").Append(c).Error
-	if err != nil {
+	tx := as.db.Begin()
+	if err := tx.Model(a).Association("Comments").Append(c).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := adjustCount(tx, a, "comments_count", 1, &a.CommentsCount); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
 		return err
 	}
 	return as.db.Where(c.ID).Preload("Player").First(c).Error
@@ -190,6 +225,23 @@ func (as *ItemStore) GetCommentsBySlug(slug string) ([]model.Comment, error) {
 	return m.Comments, nil
 }
 
+// CommentsByItems returns the comments on each of the items in ids, with
+// their authors, without a query per item.
+func (as *ItemStore) CommentsByItems(ids []uint) (map[uint][]model.Comment, error) {
+	comments := make(map[uint][]model.Comment)
+	if len(ids) == 0 {
+		return comments, nil
+	}
+	var cs []model.Comment
+	if err := as.db.Where("item_id in (?)", ids).Preload("Player").Order("id").Find(&cs).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range cs {
+		comments[c.ItemID] = append(comments[c.ItemID], c)
+	}
+	return comments, nil
+}
+
 func (as *ItemStore) GetCommentByID(id uint) (*model.Comment, error) {
 	var m model.Comment
 	if err := as.db.Where(id).First(&m).Error; err != nil {
@@ -202,19 +254,66 @@ func (as *ItemStore) GetCommentByID(id uint) (*model.Comment, error) {
 }
 
 func (as *ItemStore) DeleteComment(c *model.Comment) error {
-	return as.db.Delete(c).Error
+	tx := as.db.Begin()
+	res := tx.Delete(c)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+	a := model.Item{}
+	a.ID = c.ItemID
+	if err := adjustCount(tx, &a, "comments_count", -int(res.RowsAffected), nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
 }
 
 func (as *ItemStore) AddFavorite(a *model.Item, playerID uint) error {
-	usr := model.Player{}
-	usr.ID = playerID
-	return as.db.Model(a).Association("Favorites").Append(&usr).Error
+	tx := as.db.Begin()
+	res := db.Exec(tx, "INSERT INTO favorites (item_id, player_id) SELECT ?, ? WHERE NOT EXISTS "+
+		"(SELECT 1 FROM favorites WHERE item_id = ? AND player_id = ?)", a.ID, playerID, a.ID, playerID)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+	if err := adjustCount(tx, a, "favorites_count", int(res.RowsAffected), &a.FavoritesCount); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
 }
 
 func (as *ItemStore) RemoveFavorite(a *model.Item, playerID uint) error {
-	usr := model.Player{}
-	usr.ID = playerID
-	return as.db.Model(a).Association("Favorites").Delete(&usr).Error
+	tx := as.db.Begin()
+	res := db.Exec(tx, "DELETE FROM favorites WHERE item_id = ? AND player_id = ?", a.ID, playerID)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+	if err := adjustCount(tx, a, "favorites_count", -int(res.RowsAffected), &a.FavoritesCount); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// FavoritedBy returns which of the items in ids the player has favorited,
+// in a single query.
+func (as *ItemStore) FavoritedBy(playerID uint, ids []uint) (map[uint]bool, error) {
+	favorited := make(map[uint]bool)
+	if playerID == 0 || len(ids) == 0 {
+		return favorited, nil
+	}
+	var itemIDs []uint
+	err := as.db.Table("favorites").Where("player_id = ? AND item_id in (?)", playerID, ids).Pluck("item_id", &itemIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range itemIDs {
+		favorited[id] = true
+	}
+	return favorited, nil
 }
 
 func (as *ItemStore) ListTags() ([]model.Tag, error) {
@@ -224,3 +323,40 @@ func (as *ItemStore) ListTags() ([]model.Tag, error) {
 	}
 	return tags, nil
 }
+
+// ReconcileCounters recomputes the favorites and comments counters of all
+// items from the underlying rows and returns how many items had drifted.
+func (as *ItemStore) ReconcileCounters() (int64, error) {
+	favorites := "(SELECT count(*) FROM favorites WHERE favorites.item_id = items.id)"
+	comments := "(SELECT count(*) FROM comments WHERE comments.item_id = items.id AND comments.deleted_at IS NULL)"
+	res := db.Exec(as.db, "UPDATE items SET favorites_count = "+favorites+", comments_count = "+comments+
+		" WHERE favorites_count <> "+favorites+" OR comments_count <> "+comments)
+	return res.RowsAffected, res.Error
+}
+
+// orderBy returns the ORDER BY clause for an item list order.
+func orderBy(order item.Order) string {
+	switch order {
+	case item.OrderFavorites:
+		return "favorites_count desc, created_at desc"
+	case item.OrderComments:
+		return "comments_count desc, created_at desc"
+	default:
+		return "created_at desc"
+	}
+}
+
+// adjustCount adds delta to a counter column of the item inside tx without
+// touching updated_at, then reads the stored value back into dst if set.
+func adjustCount(tx *gorm.DB, a *model.Item, column string, delta int, dst *int) error {
+	if delta != 0 {
+		err := tx.Model(&model.Item{}).Where("id = ?", a.ID).UpdateColumn(column, gorm.Expr(column+" + ?", delta)).Error
+		if err != nil {
+			return err
+		}
+	}
+	if dst == nil {
+		return nil
+	}
+	return tx.Table("items").Where("id = ?", a.ID).Select(column).Row().Scan(dst)
+}
@@ -1,8 +1,14 @@
 package store
 
 import (
+	"context"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"golang-starter-pack/db"
 	"golang-starter-pack/model"
+	"golang-starter-pack/player"
+	"golang-starter-pack/utils"
 )
 
 type PlayerStore struct {
@@ -15,6 +21,12 @@ func NewPlayerStore(db *gorm.DB) *PlayerStore {
 	}
 }
 
+// WithContext returns a copy of the store whose SQL statements are traced
+// as children of the span in ctx.
+func (us *PlayerStore) WithContext(ctx context.Context) player.Store {
+	return &PlayerStore{db: db.WithContext(us.db, ctx)}
+}
+
 func (us *PlayerStore) GetByID(id uint) (*model.Player, error) {
 	var m model.Player
 	if err := us.db.First(&m, id).Error; err != nil {
@@ -49,29 +61,69 @@ func (us *PlayerStore) GetByUsername(username string) (*model.Player, error) {
 }
 
 func (us *PlayerStore) Create(u *model.Player) (err error) {
-	return us.db.Create(u).Error
+	return translateError(us.db.Create(u).Error)
 }
 
+// Update saves u only if the stored row still has the UpdatedAt it was
+// loaded with, so concurrent updates cannot silently overwrite each other.
 func (us *PlayerStore) Update(u *model.Player) error {
-	return us.db.Model(u).Update(u).Error
+	res := us.db.Model(u).Where("updated_at = ?", u.UpdatedAt).Omit("followers_count").Update(u)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return utils.ErrModifiedConcurrently()
+	}
+	return nil
 }
 
 func (us *PlayerStore) AddFollower(u *model.Player, followerID uint) error {
-	return us.db.Model(u).Association("Followers").Append(&model.Follow{FollowerID: followerID, FollowingID: u.ID}).Error
+	tx := us.db.Begin()
+	res := db.Exec(tx, "INSERT INTO follows (follower_id, following_id) SELECT ?, ? WHERE NOT EXISTS "+
+		"(SELECT 1 FROM follows WHERE follower_id = ? AND following_id = ?)", followerID, u.ID, followerID, u.ID)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+	if err := adjustFollowers(tx, u, int(res.RowsAffected)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
 }
 
 func (us *PlayerStore) RemoveFollower(u *model.Player, followerID uint) error {
-	f := model.Follow{
-		FollowerID:  followerID,
-		FollowingID: u.ID,
+	tx := us.db.Begin()
+	res := db.Exec(tx, "DELETE FROM follows WHERE follower_id = ? AND following_id = ?", followerID, u.ID)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
 	}
-	if err := us.db.Model(u).Association("Followers").Find(&f).Error; err != nil {
+	if err := adjustFollowers(tx, u, -int(res.RowsAffected)); err != nil {
+		tx.Rollback()
 		return err
 	}
-	if err := us.db.Delete(f).Error; err != nil {
-		return err
+	return tx.Commit().Error
+}
+
+// adjustFollowers adds delta to the player's followers counter inside tx
+// without touching updated_at and reads the stored value back.
+func adjustFollowers(tx *gorm.DB, u *model.Player, delta int) error {
+	if delta != 0 {
+		err := tx.Model(&model.Player{}).Where("id = ?", u.ID).UpdateColumn("followers_count", gorm.Expr("followers_count + ?", delta)).Error
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+	return tx.Table("players").Where("id = ?", u.ID).Select("followers_count").Row().Scan(&u.FollowersCount)
+}
+
+// ReconcileCounters recomputes the followers counter of all players from
+// the follows table and returns how many players had drifted.
+func (us *PlayerStore) ReconcileCounters() (int64, error) {
+	followers := "(SELECT count(*) FROM follows WHERE follows.following_id = players.id)"
+	res := db.Exec(us.db, "UPDATE players SET followers_count = "+followers+" WHERE followers_count <> "+followers)
+	return res.RowsAffected, res.Error
 }
 
 func (us *PlayerStore) IsFollower(playerID, followerID uint) (bool, error) {
@@ -84,3 +136,177 @@ func (us *PlayerStore) IsFollower(playerID, followerID uint) (bool, error) {
 	}
 	return true, nil
 }
+
+// FollowingSet returns which of the players in ids followerID follows, in
+// a single query.
+func (us *PlayerStore) FollowingSet(followerID uint, ids []uint) (map[uint]bool, error) {
+	following := make(map[uint]bool)
+	if followerID == 0 || len(ids) == 0 {
+		return following, nil
+	}
+	var followingIDs []uint
+	err := us.db.Model(&model.Follow{}).Where("follower_id = ? AND following_id in (?)", followerID, ids).Pluck("following_id", &followingIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range followingIDs {
+		following[id] = true
+	}
+	return following, nil
+}
+
+func (us *PlayerStore) AddLoginAttempt(a *model.LoginAttempt) error {
+	return us.db.Create(a).Error
+}
+
+func (us *PlayerStore) CountFailedLoginsByIP(ip string, since time.Time) (int, error) {
+	var count int
+	err := us.db.Model(&model.LoginAttempt{}).Where("ip = ? AND created_at > ?", ip, since).Count(&count).Error
+	return count, err
+}
+
+// FailedLoginsByEmail counts the failed attempts recorded for email and
+// returns the time of the latest.
+func (us *PlayerStore) FailedLoginsByEmail(email string) (int, *time.Time, error) {
+	var count int
+	q := us.db.Model(&model.LoginAttempt{}).Where("email = ?", email)
+	if err := q.Count(&count).Error; err != nil || count == 0 {
+		return 0, nil, err
+	}
+	var last model.LoginAttempt
+	if err := q.Order("created_at desc").First(&last).Error; err != nil {
+		return 0, nil, err
+	}
+	return count, &last.CreatedAt, nil
+}
+
+// ClaimLoginAttempt counts an attempt as failed before its credentials are
+// checked. The increment only applies while failed_logins still holds the
+// value u was read with, so of several concurrent attempts exactly one
+// claims it; the others get false. u is reloaded either way.
+func (us *PlayerStore) ClaimLoginAttempt(u *model.Player, at time.Time, lockedUntil *time.Time) (bool, error) {
+	res := us.db.Model(&model.Player{}).Where("id = ? AND failed_logins = ?", u.ID, u.FailedLogins).
+		Updates(map[string]interface{}{
+			"failed_logins":        gorm.Expr("failed_logins + 1"),
+			"last_failed_login_at": at,
+			"locked_until":         lockedUntil,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, us.reloadLoginState(u)
+}
+
+// ReleaseLoginAttempt undoes a claim whose credentials turned out right,
+// restoring the state of prev unless another attempt was claimed since.
+func (us *PlayerStore) ReleaseLoginAttempt(u, prev *model.Player) error {
+	err := us.db.Model(&model.Player{}).Where("id = ? AND failed_logins = ?", u.ID, u.FailedLogins).
+		Updates(map[string]interface{}{
+			"failed_logins":        gorm.Expr("failed_logins - 1"),
+			"last_failed_login_at": prev.LastFailedLoginAt,
+			"locked_until":         prev.LockedUntil,
+		}).Error
+	if err != nil {
+		return err
+	}
+	return us.reloadLoginState(u)
+}
+
+func (us *PlayerStore) reloadLoginState(u *model.Player) error {
+	var m model.Player
+	if err := us.db.Select("failed_logins, last_failed_login_at, locked_until").First(&m, u.ID).Error; err != nil {
+		return err
+	}
+	u.FailedLogins, u.LastFailedLoginAt, u.LockedUntil = m.FailedLogins, m.LastFailedLoginAt, m.LockedUntil
+	return nil
+}
+
+// UpdateLoginState persists the lockout bookkeeping fields, including zero
+// values, which Update skips.
+func (us *PlayerStore) UpdateLoginState(u *model.Player) error {
+	return us.db.Model(u).Updates(map[string]interface{}{
+		"failed_logins":        u.FailedLogins,
+		"last_failed_login_at": u.LastFailedLoginAt,
+		"locked_until":         u.LockedUntil,
+	}).Error
+}
+
+// UpdateTOTP persists the second factor fields, including zero values.
+func (us *PlayerStore) UpdateTOTP(u *model.Player) error {
+	return us.db.Model(u).Updates(map[string]interface{}{
+		"totp_secret":       u.TOTPSecret,
+		"totp_enabled":      u.TOTPEnabled,
+		"totp_last_counter": u.TOTPLastCounter,
+	}).Error
+}
+
+func (us *PlayerStore) ReplaceRecoveryCodes(u *model.Player, hashes []string) error {
+	tx := us.db.Begin()
+	if err := tx.Unscoped().Where("player_id = ?", u.ID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, h := range hashes {
+		if err := tx.Create(&model.RecoveryCode{PlayerID: u.ID, CodeHash: h}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// UseRecoveryCode marks a matching unused code as used and reports whether
+// one was found. The conditional update makes concurrent reuse impossible.
+func (us *PlayerStore) UseRecoveryCode(playerID uint, hash string) (bool, error) {
+	res := us.db.Model(&model.RecoveryCode{}).
+		Where("player_id = ? AND code_hash = ? AND used_at IS NULL", playerID, hash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (us *PlayerStore) CreateAPIKey(k *model.APIKey) error {
+	return translateError(us.db.Create(k).Error)
+}
+
+func (us *PlayerStore) ListAPIKeys(playerID uint) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	if err := us.db.Where(&model.APIKey{PlayerID: playerID}).Order("created_at desc").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (us *PlayerStore) GetAPIKey(playerID, id uint) (*model.APIKey, error) {
+	var m model.APIKey
+	if err := us.db.Where(&model.APIKey{PlayerID: playerID}).First(&m, id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (us *PlayerStore) GetAPIKeyByHash(h string) (*model.APIKey, error) {
+	var m model.APIKey
+	if err := us.db.Where(&model.APIKey{KeyHash: h}).First(&m).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (us *PlayerStore) TouchAPIKey(k *model.APIKey) error {
+	now := time.Now()
+	k.LastUsedAt = &now
+	return us.db.Model(k).UpdateColumn("last_used_at", now).Error
+}
+
+func (us *PlayerStore) RevokeAPIKey(k *model.APIKey) error {
+	return us.db.Delete(k).Error
+}
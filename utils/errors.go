@@ -1,48 +1,323 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"golang-starter-pack/logging"
 	"gopkg.in/go-playground/validator.v9"
 )
 
-type Error struct {
-	Errors map[string]interface{} `json:"errors"`
+// Stable machine-readable error codes. Clients switch on these, so they
+// must never change once published; messages are free to.
+const (
+	CodeBadRequest      = "bad_request"
+	CodeUnauthorized    = "unauthorized"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeConflict        = "conflict"
+	CodeValidation      = "validation_failed"
+	CodeLocked          = "locked"
+	CodePrecondition    = "precondition_failed"
+	CodeTooManyRequests = "too_many_requests"
+	CodeUnprocessable   = "unprocessable"
+	CodeInternal        = "internal_error"
+)
+
+// AppError is a domain error carrying the HTTP status it maps to, a stable
+// code and a human message. Fields holds per-field details for
+// validation and conflict errors. When Key is set the message is rendered
+// from the Messages catalog in the client's language.
+type AppError struct {
+	Status  int
+	Code    string
+	Message string
+	Key     string
+	Params  map[string]string
+	Fields  map[string]FieldError
+	Err     error
 }
 
-func NewError(err error) Error {
-	e := Error{}
-	e.Errors = make(map[string]interface{})
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	key    string
+	params map[string]string
+}
+
+// newKeyedError builds an AppError whose message comes from the catalog.
+func newKeyedError(status int, code, key string, params map[string]string) *AppError {
+	return &AppError{
+		Status:  status,
+		Code:    code,
+		Message: Messages.T(DefaultLanguage, key, params),
+		Key:     key,
+		Params:  params,
+	}
+}
+
+// NewFieldError describes a problem with one field. Its message comes
+// from the catalog entry key.
+func NewFieldError(code, key string, params map[string]string) FieldError {
+	return FieldError{Code: code, Message: Messages.T(DefaultLanguage, key, params), key: key, params: params}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+func NewNotFound(message string) *AppError {
+	return &AppError{Status: http.StatusNotFound, Code: CodeNotFound, Message: message}
+}
+
+func NewForbidden(message string) *AppError {
+	return &AppError{Status: http.StatusForbidden, Code: CodeForbidden, Message: message}
+}
+
+// NewTaken reports a uniqueness violation on field.
+func NewTaken(field string) *AppError {
+	params := map[string]string{"field": field}
+	e := newKeyedError(http.StatusConflict, CodeConflict, "error.already_taken", params)
+	e.Fields = map[string]FieldError{field: NewFieldError("taken", "error.already_taken", params)}
+	return e
+}
+
+func NewValidation(fields map[string]FieldError) *AppError {
+	e := newKeyedError(http.StatusUnprocessableEntity, CodeValidation, "error.validation_failed", nil)
+	e.Fields = fields
+	return e
+}
+
+func ErrNotFound() *AppError {
+	return newKeyedError(http.StatusNotFound, CodeNotFound, "error.resource_not_found", nil)
+}
+
+func ErrAccessForbidden() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.access_forbidden", nil)
+}
+
+func ErrAccountLocked() *AppError {
+	return newKeyedError(http.StatusLocked, CodeLocked, "error.account_locked", nil)
+}
+
+func ErrPreconditionFailed() *AppError {
+	return newKeyedError(http.StatusPreconditionFailed, CodePrecondition, "error.precondition_failed", nil)
+}
+
+// ErrModifiedConcurrently reports an update lost to a concurrent write
+// between reading and saving a resource.
+func ErrModifiedConcurrently() *AppError {
+	return newKeyedError(http.StatusConflict, CodeConflict, "error.modified_concurrently", nil)
+}
+
+func ErrTooManyAttempts() *AppError {
+	return newKeyedError(http.StatusTooManyRequests, CodeTooManyRequests, "error.too_many_attempts", nil)
+}
+
+func ErrRateLimited() *AppError {
+	return newKeyedError(http.StatusTooManyRequests, CodeTooManyRequests, "error.rate_limited", nil)
+}
+
+func ErrJWTMissing() *AppError {
+	return newKeyedError(http.StatusUnauthorized, CodeUnauthorized, "error.jwt_missing", nil)
+}
+
+func ErrJWTInvalid() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.jwt_invalid", nil)
+}
+
+func ErrAPIKeyInvalid() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.api_key_invalid", nil)
+}
+
+func ErrCSRFInvalid() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.csrf_invalid", nil)
+}
+
+// ErrScopeMissing reports an API key that was not granted the scope a
+// route requires.
+func ErrScopeMissing() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.scope_missing", nil)
+}
+
+// ErrSessionRequired reports an API key used where only a session token
+// is accepted.
+func ErrSessionRequired() *AppError {
+	return newKeyedError(http.StatusForbidden, CodeForbidden, "error.session_required", nil)
+}
+
+func ErrMalformedJSON() *AppError {
+	return newKeyedError(http.StatusBadRequest, CodeBadRequest, "error.malformed_json", nil)
+}
+
+func ErrAPIVersionUnknown() *AppError {
+	return newKeyedError(http.StatusNotFound, CodeNotFound, "error.api_version_unknown", nil)
+}
+
+func ErrAPIVersionNotAcceptable() *AppError {
+	return newKeyedError(http.StatusNotAcceptable, CodeForStatus(http.StatusNotAcceptable), "error.api_version_not_acceptable", nil)
+}
+
+func ErrTwoFactorEnabled() *AppError {
+	return newKeyedError(http.StatusUnprocessableEntity, CodeUnprocessable, "error.two_factor_enabled", nil)
+}
+
+func ErrTwoFactorNotStarted() *AppError {
+	return newKeyedError(http.StatusUnprocessableEntity, CodeUnprocessable, "error.two_factor_not_started", nil)
+}
+
+// ErrInvalidCode reports a wrong or expired two-factor or recovery code.
+func ErrInvalidCode() *AppError {
+	return newKeyedError(http.StatusUnprocessableEntity, CodeUnprocessable, "error.invalid_code", nil)
+}
+
+// AsAppError converts any error into an AppError. Errors that are not
+// already typed take the given status. Messages of server errors are
+// replaced so driver or internal details never reach clients.
+func AsAppError(err error, status int) *AppError {
+	var ae *AppError
+	if errors.As(err, &ae) {
+		return ae
+	}
 	switch v := err.(type) {
+	case validator.ValidationErrors:
+		return newValidatorAppError(v)
 	case *echo.HTTPError:
-		e.Errors["body"] = v.Message
-	default:
-		e.Errors["body"] = v.Error()
+		return &AppError{Status: v.Code, Code: CodeForStatus(v.Code), Message: fmt.Sprint(v.Message), Err: err}
 	}
-	return e
+	if status >= http.StatusInternalServerError {
+		ae = newKeyedError(status, CodeForStatus(status), "error.internal", nil)
+		ae.Err = err
+		return ae
+	}
+	return &AppError{Status: status, Code: CodeForStatus(status), Message: err.Error(), Err: err}
+}
+
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusLocked:
+		return CodeLocked
+	case http.StatusPreconditionFailed:
+		return CodePrecondition
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
 }
 
-func NewValidatorError(err error) Error {
-	e := Error{}
-	e.Errors = make(map[string]interface{})
-	errs := err.(validator.ValidationErrors)
+// Error is the JSON body of every error response. Body repeats Message for
+// clients written against the RealWorld "errors.body" convention.
+type Error struct {
+	Errors ErrorBody `json:"errors"`
+}
+
+type ErrorBody struct {
+	Code    string                `json:"code"`
+	Message string                `json:"message"`
+	Body    string                `json:"body"`
+	Fields  map[string]FieldError `json:"fields,omitempty"`
+}
+
+func (e *AppError) Response() Error {
+	return e.Localize(DefaultLanguage)
+}
+
+// Localize renders the response body in lang.
+func (e *AppError) Localize(lang string) Error {
+	msg := e.Message
+	if e.Key != "" {
+		msg = Messages.T(lang, e.Key, e.Params)
+	}
+	var fields map[string]FieldError
+	if e.Fields != nil {
+		fields = make(map[string]FieldError, len(e.Fields))
+		for name, f := range e.Fields {
+			if f.key != "" {
+				f.Message = Messages.T(lang, f.key, f.params)
+			}
+			fields[name] = f
+		}
+	}
+	return Error{ErrorBody{Code: e.Code, Message: msg, Body: msg, Fields: fields}}
+}
+
+// RenderError writes err in the language named by the request's
+// Accept-Language header, using the status of its domain type, or status
+// when err is untyped.
+func RenderError(c echo.Context, status int, err error) error {
+	e := AsAppError(err, status)
+	if e.Status >= http.StatusInternalServerError {
+		logging.FromContext(c.Request().Context()).Error("internal error", "err", err, "status", e.Status)
+	}
+	lang := Messages.Match(c.Request().Header.Get("Accept-Language"))
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(e.Status)
+	}
+	return c.JSON(e.Status, e.Localize(lang))
+}
+
+// NewError renders err. Untyped errors are rendered as unprocessable; use
+// AsAppError directly when the status is known.
+func NewError(err error) Error {
+	return AsAppError(err, http.StatusUnprocessableEntity).Response()
+}
+
+func newValidatorAppError(errs validator.ValidationErrors) *AppError {
+	fields := make(map[string]FieldError)
 	for _, v := range errs {
-		e.Errors[v.Field()] = fmt.Sprintf("%v", v.Tag())
+		key := "validation." + v.Tag()
+		if !validationKeys[v.Tag()] {
+			key = "validation.invalid"
+		}
+		fields[v.Field()] = NewFieldError(v.Tag(), key, map[string]string{"param": v.Param()})
 	}
-	return e
+	return NewValidation(fields)
+}
+
+// validationKeys lists the validator tags with a dedicated message; other
+// tags fall back to "validation.invalid".
+var validationKeys = map[string]bool{
+	"required":    true,
+	"email":       true,
+	"min":         true,
+	"max":         true,
+	"oneof":       true,
+	"url":         true,
+	"username":    true,
+	"notreserved": true,
+	"password":    true,
+	"tag":         true,
 }
 
 func AccessForbidden() Error {
-	e := Error{}
-	e.Errors = make(map[string]interface{})
-	e.Errors["body"] = "access forbidden"
-	return e
+	return ErrAccessForbidden().Response()
 }
 
 func NotFound() Error {
-	e := Error{}
-	e.Errors = make(map[string]interface{})
-	e.Errors["body"] = "resource not found"
-	return e
+	return ErrNotFound().Response()
 }
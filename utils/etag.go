@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RepresentationETag returns a strong ETag of v's JSON form as sent in
+// variant, which names whatever else shapes the body, such as its format.
+// Any change to a field of the body changes the tag.
+func RepresentationETag(variant string, v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return ContentETag(variant, b), nil
+}
+
+// ContentETag returns a strong ETag of body as sent in variant.
+func ContentETag(variant string, body []byte) string {
+	sum := sha1.New()
+	sum.Write([]byte(variant))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	return `"` + hex.EncodeToString(sum.Sum(nil)[:10]) + `"`
+}
+
+// NotModified sets the ETag header and reports whether the request's
+// If-None-Match already names it, in which case it has written 304 and the
+// caller should return without a body.
+func NotModified(c echo.Context, etag string) bool {
+	c.Response().Header().Set("ETag", etag)
+	m := c.Request().Method
+	if m != http.MethodGet && m != http.MethodHead {
+		return false
+	}
+	if !etagMatch(c.Request().Header.Get("If-None-Match"), etag, true) {
+		return false
+	}
+	c.NoContent(http.StatusNotModified)
+	return true
+}
+
+// CheckIfMatch returns a 412 error when the request carries an If-Match
+// header that does not name the resource's current ETag. Requests without
+// If-Match pass.
+func CheckIfMatch(c echo.Context, etag string) error {
+	h := c.Request().Header.Get("If-Match")
+	if h == "" || etagMatch(h, etag, false) {
+		return nil
+	}
+	c.Response().Header().Set("ETag", etag)
+	return ErrPreconditionFailed()
+}
+
+// etagMatch compares a list header against etag. If-None-Match uses weak
+// comparison, If-Match strong.
+func etagMatch(header, etag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" {
+			return true
+		}
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		} else if strings.HasPrefix(t, "W/") {
+			continue
+		}
+		if t == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when a request names no supported language and
+// for keys missing from the requested catalog.
+const DefaultLanguage = "en"
+
+// Catalog maps message keys to templates. Templates reference params as
+// {name}.
+type Catalog map[string]string
+
+// Translator holds message catalogs by language tag.
+type Translator struct {
+	mu       sync.RWMutex
+	catalogs map[string]Catalog
+}
+
+// Messages is the translator used for error and validation messages. It
+// ships with English and Spanish; more languages or overrides can be
+// loaded at startup with LoadDir.
+var Messages = NewTranslator(map[string]Catalog{
+	"en": {
+		"error.resource_not_found":         "resource not found",
+		"error.access_forbidden":           "access forbidden",
+		"error.account_locked":             "account temporarily locked",
+		"error.too_many_attempts":          "too many login attempts",
+		"error.rate_limited":               "rate limit exceeded",
+		"error.already_taken":              "{field} already taken",
+		"error.validation_failed":          "validation failed",
+		"error.internal":                   "internal server error",
+		"error.precondition_failed":        "resource has changed since it was fetched",
+		"error.modified_concurrently":      "resource was modified by another request",
+		"error.jwt_missing":                "missing or malformed jwt",
+		"error.jwt_invalid":                "invalid or expired jwt",
+		"error.api_key_invalid":            "invalid or revoked api key",
+		"error.csrf_invalid":               "missing or invalid csrf token",
+		"error.scope_missing":              "api key is missing the required scope",
+		"error.session_required":           "this action requires a session token",
+		"error.malformed_json":             "malformed JSON body",
+		"error.api_version_unknown":        "unknown api version",
+		"error.api_version_not_acceptable": "unknown api version in accept header",
+		"error.two_factor_enabled":         "two-factor authentication is already enabled",
+		"error.two_factor_not_started":     "two-factor enrollment has not been started",
+		"error.invalid_code":               "invalid code",
+		"validation.required":              "is required",
+		"validation.email":                 "must be a valid email address",
+		"validation.min":                   "must be at least {param} long",
+		"validation.max":                   "must be at most {param} long",
+		"validation.oneof":                 "must be one of: {param}",
+		"validation.invalid":               "is invalid",
+		"validation.url":                   "must be a valid URL",
+		"validation.username":              "may only contain letters, digits, \"_\" and \"-\"",
+		"validation.notreserved":           "is reserved",
+		"validation.password":              "must be at least 8 characters and contain a letter and a digit",
+		"validation.tag":                   "must be lowercase letters and digits separated by single dashes, at most 32 characters",
+		"validation.type":                  "must be of type {param}",
+		"validation.unknown":               "is not a known field",
+		"validation.minimum":               "must be at least {param}",
+		"validation.maximum":               "must be at most {param}",
+		"validation.min_items":             "must have at least {param} items",
+		"validation.max_items":             "must have at most {param} items",
+	},
+	"es": {
+		"error.resource_not_found":         "recurso no encontrado",
+		"error.access_forbidden":           "acceso denegado",
+		"error.account_locked":             "cuenta bloqueada temporalmente",
+		"error.too_many_attempts":          "demasiados intentos de inicio de sesión",
+		"error.rate_limited":               "límite de solicitudes excedido",
+		"error.already_taken":              "{field} ya está en uso",
+		"error.validation_failed":          "la validación falló",
+		"error.internal":                   "error interno del servidor",
+		"error.precondition_failed":        "el recurso cambió desde que se obtuvo",
+		"error.modified_concurrently":      "el recurso fue modificado por otra solicitud",
+		"error.jwt_missing":                "jwt ausente o mal formado",
+		"error.jwt_invalid":                "jwt no válido o caducado",
+		"error.api_key_invalid":            "clave de api no válida o revocada",
+		"error.csrf_invalid":               "token csrf ausente o no válido",
+		"error.scope_missing":              "a la clave de api le falta el permiso requerido",
+		"error.session_required":           "esta acción requiere un token de sesión",
+		"error.malformed_json":             "cuerpo JSON mal formado",
+		"error.api_version_unknown":        "versión de api desconocida",
+		"error.api_version_not_acceptable": "versión de api desconocida en la cabecera accept",
+		"error.two_factor_enabled":         "la autenticación de dos factores ya está activada",
+		"error.two_factor_not_started":     "no se ha iniciado el registro de dos factores",
+		"error.invalid_code":               "código no válido",
+		"validation.required":              "es obligatorio",
+		"validation.email":                 "debe ser un correo electrónico válido",
+		"validation.min":                   "debe tener al menos {param} de longitud",
+		"validation.max":                   "debe tener como máximo {param} de longitud",
+		"validation.oneof":                 "debe ser uno de: {param}",
+		"validation.invalid":               "no es válido",
+		"validation.url":                   "debe ser una URL válida",
+		"validation.username":              "solo puede contener letras, dígitos, \"_\" y \"-\"",
+		"validation.notreserved":           "está reservado",
+		"validation.password":              "debe tener al menos 8 caracteres e incluir una letra y un dígito",
+		"validation.tag":                   "debe contener letras minúsculas y dígitos separados por guiones simples, con un máximo de 32 caracteres",
+		"validation.type":                  "debe ser de tipo {param}",
+		"validation.unknown":               "no es un campo conocido",
+		"validation.minimum":               "debe ser al menos {param}",
+		"validation.maximum":               "debe ser como máximo {param}",
+		"validation.min_items":             "debe tener al menos {param} elementos",
+		"validation.max_items":             "debe tener como máximo {param} elementos",
+	},
+})
+
+func NewTranslator(catalogs map[string]Catalog) *Translator {
+	t := &Translator{catalogs: make(map[string]Catalog)}
+	for lang, c := range catalogs {
+		t.Add(lang, c)
+	}
+	return t
+}
+
+// Add merges c into the catalog for lang, overriding existing keys.
+func (t *Translator) Add(lang string, c Catalog) {
+	lang = strings.ToLower(lang)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.catalogs[lang] == nil {
+		t.catalogs[lang] = make(Catalog)
+	}
+	for k, v := range c {
+		t.catalogs[lang][k] = v
+	}
+}
+
+// LoadDir merges every <lang>.json file in dir, each a flat object of
+// key to template, so catalogs can be added or edited without a rebuild.
+func (t *Translator) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		var c Catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			return err
+		}
+		t.Add(strings.TrimSuffix(filepath.Base(f), ".json"), c)
+	}
+	return nil
+}
+
+// Match picks the best supported language for an Accept-Language header,
+// honouring q-values and falling back from "es-MX" to "es".
+func (t *Translator) Match(acceptLanguage string) string {
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{tag, q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, p := range prefs {
+		if _, ok := t.catalogs[p.tag]; ok {
+			return p.tag
+		}
+		if i := strings.Index(p.tag, "-"); i > 0 {
+			if _, ok := t.catalogs[p.tag[:i]]; ok {
+				return p.tag[:i]
+			}
+		}
+	}
+	return DefaultLanguage
+}
+
+// T renders key in lang, falling back to DefaultLanguage and finally to the
+// key itself.
+func (t *Translator) T(lang, key string, params map[string]string) string {
+	t.mu.RLock()
+	msg, ok := t.catalogs[lang][key]
+	if !ok {
+		msg, ok = t.catalogs[DefaultLanguage][key]
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return key
+	}
+	for k, v := range params {
+		msg = strings.Replace(msg, "{"+k+"}", v, -1)
+	}
+	return msg
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -8,11 +12,73 @@ import (
 
 var JWTSecret = []byte("!!SECRET!!")
 
+const (
+	JWTIssuer   = "golang-starter-pack"
+	JWTAudience = "golang-starter-pack/api"
+	SessionTTL  = time.Hour * 72
+)
+
+// ChallengePurpose marks tokens issued after the password step of a two-step
+// login. They are only accepted by the second factor endpoint.
+const ChallengePurpose = "2fa"
+
 func GenerateJWT(id uint) string {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["id"] = id
-	claims["exp"] = time.Now().Add(time.Hour * 72).Unix()
-	t, _ := token.SignedString(JWTSecret)
+	claims := newClaims(id, SessionTTL)
+	t, _ := DefaultKeySet.Sign(claims)
 	return t
 }
+
+func GenerateChallengeJWT(id uint) string {
+	claims := newClaims(id, time.Minute*5)
+	claims["purpose"] = ChallengePurpose
+	t, _ := DefaultKeySet.Sign(claims)
+	return t
+}
+
+// newClaims returns the registered claims shared by every token. "id" is
+// kept alongside "sub" for clients that still read it.
+func newClaims(id uint, ttl time.Duration) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"id":  id,
+		"sub": strconv.FormatUint(uint64(id), 10),
+		"iss": JWTIssuer,
+		"aud": JWTAudience,
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+		"jti": newJTI(),
+	}
+}
+
+func newJTI() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// PlayerIDFromClaims reads the subject of a validated token.
+func PlayerIDFromClaims(claims jwt.MapClaims) (uint, error) {
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return 0, errors.New("missing subject")
+	}
+	id, err := strconv.ParseUint(sub, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// ParseChallengeJWT returns the player id carried by a valid challenge token.
+func ParseChallengeJWT(t string) (uint, error) {
+	token, err := jwt.Parse(t, DefaultKeySet.Keyfunc)
+	if err != nil {
+		return 0, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["purpose"] != ChallengePurpose ||
+		!claims.VerifyIssuer(JWTIssuer, true) || !claims.VerifyAudience(JWTAudience, true) {
+		return 0, errors.New("invalid challenge token")
+	}
+	return PlayerIDFromClaims(claims)
+}
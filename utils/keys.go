@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningKey is a key used to sign and verify tokens, identified by the
+// "kid" header. Symmetric keys set Public to the shared secret.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// KeySet holds every key that tokens may be verified with and marks one of
+// them as active for signing. Rotating means adding a new key, making it
+// active, and removing the old one once its tokens have expired.
+type KeySet struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*SigningKey
+}
+
+// DefaultKeyID names the HS256 key backed by JWTSecret. The secret is
+// public, so LoadKeyDir drops the key once real keys are loaded.
+const DefaultKeyID = "hs256-default"
+
+// DefaultKeySet signs and verifies session tokens. Until keys are loaded
+// it holds a single HS256 key backed by JWTSecret.
+var DefaultKeySet = NewDefaultKeySet()
+
+// NewDefaultKeySet returns a key set holding only the JWTSecret key.
+func NewDefaultKeySet() *KeySet {
+	return NewKeySet(&SigningKey{
+		ID:      DefaultKeyID,
+		Method:  jwt.SigningMethodHS256,
+		Private: JWTSecret,
+		Public:  JWTSecret,
+	})
+}
+
+func NewKeySet(active *SigningKey) *KeySet {
+	s := &KeySet{keys: make(map[string]*SigningKey)}
+	s.Add(active)
+	s.active = active.ID
+	return s
+}
+
+func (s *KeySet) Add(k *SigningKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[k.ID] = k
+}
+
+func (s *KeySet) SetActive(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[kid]; !ok {
+		return fmt.Errorf("unknown key id %q", kid)
+	}
+	s.active = kid
+	return nil
+}
+
+// Remove drops a key so tokens signed with it stop validating. The active
+// key cannot be removed.
+func (s *KeySet) Remove(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kid == s.active {
+		return errors.New("cannot remove the active key")
+	}
+	delete(s.keys, kid)
+	return nil
+}
+
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	s.mu.RLock()
+	k := s.keys[s.active]
+	s.mu.RUnlock()
+	token := jwt.NewWithClaims(k.Method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.Private)
+}
+
+// Keyfunc resolves the verification key from the token's kid and refuses
+// tokens whose alg does not match the key, which blocks alg confusion.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	s.mu.RLock()
+	k, ok := s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return k.Public, nil
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public halves of all asymmetric keys. Symmetric keys are
+// never published.
+func (s *KeySet) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set := JWKS{Keys: make([]JWK, 0)}
+	enc := base64.RawURLEncoding
+	for _, k := range s.keys {
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA", Kid: k.ID, Alg: k.Method.Alg(), Use: "sig",
+				N: enc.EncodeToString(pub.N.Bytes()),
+				E: enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP", Kid: k.ID, Alg: k.Method.Alg(), Use: "sig",
+				Crv: "Ed25519", X: enc.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+// NewSigningKeyFromPEM builds a key from a PKCS#1 or PKCS#8 private key.
+// RSA keys sign with RS256 and Ed25519 keys with EdDSA.
+func NewSigningKeyFromPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	var priv interface{}
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch p := priv.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Private: p, Public: p.Public()}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: SigningMethodEdDSA, Private: p, Public: p.Public().(ed25519.PublicKey)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", priv)
+}
+
+// LoadKeyDir adds every <kid>.pem private key found in dir to the set,
+// activates the one named active and removes the JWTSecret key, so that
+// tokens signed with the well-known secret stop validating.
+func (s *KeySet) LoadKeyDir(dir, active string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		k, err := NewSigningKeyFromPEM(strings.TrimSuffix(filepath.Base(f), ".pem"), data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+		s.Add(k)
+	}
+	if active == DefaultKeyID {
+		return fmt.Errorf("key %q cannot be active once keys are loaded", DefaultKeyID)
+	}
+	if err := s.SetActive(active); err != nil {
+		return err
+	}
+	return s.Remove(DefaultKeyID)
+}
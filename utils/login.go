@@ -0,0 +1,43 @@
+package utils
+
+import "time"
+
+// LoginPolicy controls how failed logins are throttled.
+type LoginPolicy struct {
+	// BaseDelay is the wait imposed after the first failure; it doubles
+	// with every further failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxFailures is the number of consecutive failures that locks the
+	// account for LockoutDuration.
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// IPMaxFailures is the number of failures allowed from a single IP
+	// within IPWindow, regardless of the account targeted.
+	IPMaxFailures int
+	IPWindow      time.Duration
+}
+
+var DefaultLoginPolicy = LoginPolicy{
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Minute,
+	MaxFailures:     10,
+	LockoutDuration: 15 * time.Minute,
+	IPMaxFailures:   50,
+	IPWindow:        15 * time.Minute,
+}
+
+// Delay returns how long to wait after the given number of consecutive failures.
+func (p LoginPolicy) Delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	d := p.BaseDelay
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return d
+}
@@ -0,0 +1,303 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MIMEMergePatch = "application/merge-patch+json"
+	MIMEJSONPatch  = "application/json-patch+json"
+)
+
+var ErrInvalidPatch = errors.New("invalid patch document")
+
+// MergePatch applies an RFC 7396 merge patch to doc. Members set to null
+// are removed, objects are merged recursively and anything else replaces
+// the target value.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var d, p interface{}
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrInvalidPatch
+	}
+	return json.Marshal(mergeValue(d, p))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeValue(t[k], v)
+	}
+	return t
+}
+
+type patchOp struct {
+	Op    string
+	Path  string
+	From  string
+	Value json.RawMessage
+	// HasValue tells a missing value apart from a null one, which "add",
+	// "replace" and "test" accept like any other value.
+	HasValue bool
+}
+
+func (op *patchOp) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	for name, dst := range map[string]*string{"op": &op.Op, "path": &op.Path, "from": &op.From} {
+		if raw, ok := m[name]; ok {
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return err
+			}
+		}
+	}
+	op.Value, op.HasValue = m["value"]
+	return nil
+}
+
+// JSONPatch applies an RFC 6902 patch to doc. Operations run in order and
+// the whole patch fails if any of them does.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	var d interface{}
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, ErrInvalidPatch
+	}
+	for i, op := range ops {
+		var err error
+		d, err = applyOp(d, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+func applyOp(doc interface{}, op patchOp) (interface{}, error) {
+	value := func() (interface{}, error) {
+		if !op.HasValue {
+			return nil, errors.New("missing value")
+		}
+		var v interface{}
+		err := json.Unmarshal(op.Value, &v)
+		return v, err
+	}
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return pointerSet(doc, op.Path, v, true)
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pointerGet(doc, op.Path); err != nil {
+			return nil, err
+		}
+		return pointerSet(doc, op.Path, v, false)
+	case "remove":
+		return pointerRemove(doc, op.Path)
+	case "move":
+		v, err := pointerGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = pointerRemove(doc, op.From); err != nil {
+			return nil, err
+		}
+		return pointerSet(doc, op.Path, v, true)
+	case "copy":
+		v, err := pointerGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return pointerSet(doc, op.Path, deepCopy(v), true)
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := pointerGet(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(got, v) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", p)
+	}
+	parts := strings.Split(p[1:], "/")
+	for i, s := range parts {
+		parts[i] = strings.Replace(strings.Replace(s, "~1", "/", -1), "~0", "~", -1)
+	}
+	return parts, nil
+}
+
+func pointerGet(doc interface{}, p string) (interface{}, error) {
+	parts, err := parsePointer(p)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, k := range parts {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[k]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", p)
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("path %q not found", p)
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+	}
+	return cur, nil
+}
+
+// pointerSet stores v at p and returns the new document. insert selects
+// "add" semantics for arrays, where the value is inserted rather than
+// replacing the element.
+func pointerSet(doc interface{}, p string, v interface{}, insert bool) (interface{}, error) {
+	parts, err := parsePointer(p)
+	if err != nil {
+		return nil, err
+	}
+	return setIn(doc, parts, v, insert, p)
+}
+
+func setIn(cur interface{}, parts []string, v interface{}, insert bool, p string) (interface{}, error) {
+	if len(parts) == 0 {
+		return v, nil
+	}
+	k, rest := parts[0], parts[1:]
+	switch c := cur.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			c[k] = v
+			return c, nil
+		}
+		child, ok := c[k]
+		if !ok {
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+		nv, err := setIn(child, rest, v, insert, p)
+		if err != nil {
+			return nil, err
+		}
+		c[k] = nv
+		return c, nil
+	case []interface{}:
+		if len(rest) == 0 && insert && k == "-" {
+			return append(c, v), nil
+		}
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i > len(c) || (i == len(c) && !(insert && len(rest) == 0)) {
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+		if len(rest) == 0 {
+			if insert {
+				c = append(c, nil)
+				copy(c[i+1:], c[i:])
+			}
+			c[i] = v
+			return c, nil
+		}
+		nv, err := setIn(c[i], rest, v, insert, p)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = nv
+		return c, nil
+	}
+	return nil, fmt.Errorf("path %q not found", p)
+}
+
+func pointerRemove(doc interface{}, p string) (interface{}, error) {
+	parts, err := parsePointer(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	parent, err := pointerGet(doc, "/"+strings.Join(escapePointer(parts[:len(parts)-1]), "/"))
+	if len(parts) == 1 {
+		parent, err = doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k := parts[len(parts)-1]
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := c[k]; !ok {
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+		delete(c, k)
+		return doc, nil
+	case []interface{}:
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(c) {
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+		return setIn(doc, parts[:len(parts)-1], append(c[:i:i], c[i+1:]...), false, p)
+	}
+	return nil, fmt.Errorf("path %q not found", p)
+}
+
+func escapePointer(parts []string) []string {
+	out := make([]string, len(parts))
+	for i, s := range parts {
+		out[i] = strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+	}
+	return out
+}
+
+func deepCopy(v interface{}) interface{} {
+	b, _ := json.Marshal(v)
+	var out interface{}
+	json.Unmarshal(b, &out)
+	return out
+}
@@ -0,0 +1,24 @@
+// Package version reports how the running binary was built. Commit and
+// BuildTime are set at link time:
+//
+//	go build -ldflags "-X golang-starter-pack/version.Commit=$(git rev-parse HEAD) \
+//		-X golang-starter-pack/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import "runtime"
+
+var (
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the build of the running binary.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+func Get() Info {
+	return Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+}